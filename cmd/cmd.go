@@ -2,27 +2,376 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"math"
 	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jwowillo/viztransform/geometry"
+	"github.com/jwowillo/viztransform/parse"
+	"github.com/jwowillo/viztransform/transform"
 )
 
 // Fail with error err.
+//
+// A parse.Error is printed like a compiler would as described by FailIn.
 func Fail(err error) {
-	fmt.Fprintf(os.Stderr, "%v\n", err)
+	FailIn("", err)
+}
+
+// FailIn fails with error err from reading the input called name.
+//
+// A parse.Error is printed as 'name:line:column: error' followed by the line
+// with the problem, a '^' under where the problem starts, and the hint if
+// there is one. The name is left out if it's empty and is the path of the
+// included file instead if the problem is in one.
+func FailIn(name string, err error) {
+	var pe *parse.Error
+	if !errors.As(err, &pe) {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	if pe.Path != "" {
+		name = pe.Path
+	}
+	if name != "" {
+		fmt.Fprintf(os.Stderr, "%s:", name)
+	}
+	fmt.Fprintf(os.Stderr, "%v\n", pe)
+	if pe.Snippet != "" {
+		fmt.Fprintf(os.Stderr, "%s\n%s^\n", pe.Snippet, indent(pe.Snippet, pe.Column))
+	}
+	if pe.Hint != "" {
+		fmt.Fprintf(os.Stderr, "hint: %s\n", pe.Hint)
+	}
 	os.Exit(1)
 }
 
+// indent returns the whitespace that lines up with the character at column in
+// line x.
+//
+// Tabs in x are kept so the whitespace lines up no matter how wide tabs are.
+func indent(x string, column int) string {
+	var b strings.Builder
+	for i, r := range []rune(x) {
+		if i >= column-1 {
+			break
+		}
+		if r == '\t' {
+			b.WriteRune('\t')
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+	return b.String()
+}
+
 // Init command with description u by setting a usage func.
 func Init(u string) {
 	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, u)
-		fmt.Fprintf(os.Stderr, "\n")
-		fmt.Fprintf(os.Stderr, transformations)
+		fmt.Fprint(os.Stderr, u)
+		fmt.Fprint(os.Stderr, "\n")
+		fmt.Fprint(os.Stderr, transformations)
+		if hasTolerance {
+			fmt.Fprint(os.Stderr, tolerance)
+		}
+		if hasAngleUnit {
+			fmt.Fprint(os.Stderr, angles)
+		}
+		if hasInput || hasOutput {
+			fmt.Fprint(os.Stderr, formats)
+		}
+		if hasInput {
+			fmt.Fprint(os.Stderr, inputUsage)
+		}
+		if hasOutput {
+			fmt.Fprint(os.Stderr, outputUsage)
+		}
+		if hasInput || hasOutput {
+			fmt.Fprint(os.Stderr, formatDetails)
+		}
 	}
 	flag.Parse()
 }
 
+// Tolerance adds flags for setting a geometry.Tolerance to the command and
+// returns the geometry.Tolerance they set.
+//
+// Must be called before Init. The geometry.Tolerance is
+// geometry.DefaultTolerance until Init parses the flags. Each flag must be more
+// than 0 so nothing set by them is stricter than exact equality.
+func Tolerance() *geometry.Tolerance {
+	hasTolerance = true
+	tol := geometry.DefaultTolerance()
+	flag.Var(
+		(*positive)(&tol.Absolute),
+		"epsilon",
+		"absolute tolerance for comparing numbers",
+	)
+	flag.Var(
+		(*positive)(&tol.Relative),
+		"relative",
+		"relative tolerance for comparing numbers and angles",
+	)
+	return &tol
+}
+
+// hasTolerance is true if Tolerance was called.
+var hasTolerance bool
+
+// positive is a geometry.Number set by a flag that must be more than 0.
+type positive geometry.Number
+
+// String returns the positive as a decimal.
+func (n *positive) String() string {
+	return strconv.FormatFloat(float64(*n), 'g', -1, 64)
+}
+
+// Set the positive to the number x.
+//
+// Returns errPositive if x isn't a number more than 0.
+func (n *positive) Set(x string) error {
+	v, err := strconv.ParseFloat(x, 64)
+	if err != nil || !(v > 0) || math.IsInf(v, 1) {
+		return errPositive
+	}
+	*n = positive(v)
+	return nil
+}
+
+// errPositive is the error when a tolerance flag isn't a number more than 0.
+var errPositive = errors.New("must be a number more than 0")
+
+// AngleUnit adds a flag for setting the geometry.AngleUnit angles are printed
+// in to the command and returns the geometry.AngleUnit it sets.
+//
+// Must be called before Init. The geometry.AngleUnit is geometry.Radians until
+// Init parses the flags.
+func AngleUnit() *geometry.AngleUnit {
+	hasAngleUnit = true
+	u := geometry.Radians
+	flag.Var(
+		(*angleUnit)(&u),
+		"angle",
+		"unit angles are printed in which is rad, deg, or pi",
+	)
+	return &u
+}
+
+// hasAngleUnit is true if AngleUnit was called.
+var hasAngleUnit bool
+
+// angleUnit is a geometry.AngleUnit set by a flag by its name.
+type angleUnit geometry.AngleUnit
+
+// String returns the name of the angleUnit.
+func (u *angleUnit) String() string {
+	for name, v := range angleUnits {
+		if angleUnit(v) == *u {
+			return name
+		}
+	}
+	return ""
+}
+
+// Set the angleUnit to the geometry.AngleUnit named x.
+//
+// Returns errAngleUnit if x isn't a name of a geometry.AngleUnit.
+func (u *angleUnit) Set(x string) error {
+	v, ok := angleUnits[x]
+	if !ok {
+		return errAngleUnit
+	}
+	*u = angleUnit(v)
+	return nil
+}
+
+// angleUnits are the names of each geometry.AngleUnit.
+var angleUnits = map[string]geometry.AngleUnit{
+	"rad": geometry.Radians,
+	"deg": geometry.Degrees,
+	"pi":  geometry.PiRadians,
+}
+
+// errAngleUnit is the error when the angle flag isn't the name of a
+// geometry.AngleUnit.
+var errAngleUnit = errors.New("must be rad, deg, or pi")
+
+// Format transformations are read and written in.
+type Format int
+
+// Formats.
+const (
+	// Text is the format described by the usage.
+	Text Format = iota
+	// JSON is read as described by parse.JSON and written like a
+	// transform.Params' JSON.
+	JSON
+	// SVG is read as described by parse.SVG and written by parse.FormatSVG.
+	SVG
+	// CSS is read as described by parse.CSS and written by parse.FormatCSS.
+	CSS
+)
+
+// InputFlag adds a flag for setting the Format transformations are read in to
+// the command and returns the Format it sets.
+//
+// Must be called before Init. The Format is Text until Init parses the flags.
+func InputFlag() *Format {
+	hasInput = true
+	f := Text
+	flag.Var(
+		&f,
+		"input",
+		"format transformations are read in which is text, json, svg, or css",
+	)
+	return &f
+}
+
+// hasInput is true if InputFlag was called.
+var hasInput bool
+
+// OutputFlag adds a flag for setting the Format transformations are written in
+// to the command and returns the Format it sets.
+//
+// Must be called before Init. The Format is Text until Init parses the flags.
+func OutputFlag() *Format {
+	hasOutput = true
+	f := Text
+	flag.Var(
+		&f,
+		"output",
+		"format transformations are written in which is text, json, svg, or css",
+	)
+	return &f
+}
+
+// hasOutput is true if OutputFlag was called.
+var hasOutput bool
+
+// String returns the name of the Format.
+func (f *Format) String() string {
+	for name, v := range formatNames {
+		if v == *f {
+			return name
+		}
+	}
+	return ""
+}
+
+// Set the Format to the Format named x.
+//
+// Returns errFormat if x isn't a name of a Format.
+func (f *Format) Set(x string) error {
+	v, ok := formatNames[x]
+	if !ok {
+		return errFormat
+	}
+	*f = v
+	return nil
+}
+
+// formatNames are the names of each Format.
+var formatNames = map[string]Format{
+	"text": Text,
+	"json": JSON,
+	"svg":  SVG,
+	"css":  CSS,
+}
+
+// errFormat is the error when the format flag isn't the name of a Format.
+var errFormat = errors.New("must be text, json, svg, or css")
+
+// Read a transform.Transformation in the Format from r where geometry.Numbers
+// are compared within geometry.Tolerance tol.
+//
+// Returns the errors the parse package's function for the Format does.
+func (f Format) Read(
+	r io.Reader,
+	tol geometry.Tolerance,
+) (transform.Transformation, error) {
+	switch f {
+	case JSON:
+		return parse.JSONWithin(r, tol)
+	case SVG, CSS:
+		bs, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		if f == SVG {
+			return parse.SVGWithin(string(bs), tol)
+		}
+		return parse.CSSWithin(string(bs), tol)
+	}
+	return parse.TransformationWithin(r, tol)
+}
+
+// ReadFile reads a transform.Transformation in the Format from the file at
+// path like Read.
+//
+// Included paths in Text are relative to the file's directory.
+func (f Format) ReadFile(
+	path string,
+	tol geometry.Tolerance,
+) (transform.Transformation, error) {
+	if f == Text {
+		return parse.TransformationFileWithin(path, tol)
+	}
+	r, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return f.Read(r, tol)
+}
+
+// Println writes the simplified transform.Transformation t described within
+// geometry.Tolerance tol in the Format to STDOUT followed by a newline.
+//
+// Angles are in geometry.AngleUnit u in Text, in radians in JSON, and in
+// degrees in SVG and CSS. Returns the transform.InstabilityError
+// transform.DescribeWithinE does if t can't be described.
+func (f Format) Println(
+	t transform.Transformation,
+	tol geometry.Tolerance,
+	u geometry.AngleUnit,
+) error {
+	var b strings.Builder
+	switch f {
+	case JSON:
+		p, err := transform.DescribeWithinE(t, tol)
+		if err != nil {
+			return err
+		}
+		bs, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+		b.Write(bs)
+	case SVG:
+		if err := parse.FormatSVGWithin(&b, t, tol); err != nil {
+			return err
+		}
+	case CSS:
+		if err := parse.FormatCSSWithin(&b, t, tol); err != nil {
+			return err
+		}
+	default:
+		p, err := transform.DescribeWithinE(t, tol)
+		if err != nil {
+			return err
+		}
+		b.WriteString(transform.StringIn(p, u))
+	}
+	_, err := fmt.Println(b.String())
+	return err
+}
+
 // transformations usage string.
 const transformations = `
 Transformations:
@@ -33,4 +382,88 @@ Transformations:
 	  around the point.
 	- GlideReflection({(ax ay) (bx by)}, <i j>): Reflects points across the
 	  line and translates by the vector.
+	- Compose(t, ...): Does each transformation in order.
+	- Inverse(t): Undoes the transformation.
+	- Power(t, n): Does the transformation n times where n is an integer.
+	- Conjugate(a, b): Undoes b, does a, and then does b.
+
+	Each line holds one transformation and blank lines are skipped. '#'
+	starts a comment that runs to the end of the line. Spaces can go between
+	any parts and a transformation can be split over lines inside its
+	brackets. The last argument can be followed by a ','.
+
+	A line like 'let name = x' defines a name that later lines can use in
+	place of x which is a line, point, vector, number, or transformation.
+	A line like 'include "other.txt"' reads the lines of the other file in
+	its place. Included paths are relative to the including file or to the
+	current directory when reading from STDIN.
+
+	Numbers can use the constants pi, tau, and deg, which is pi/180, and
+	'+', '-', '*', '/', and parentheses like 'pi/3' or '(1+2)*3'. A number
+	right before a constant is multiplied by it like '90deg' or '2pi'. A
+	'+' or '-' with a space before it and not after it is a sign so
+	'(1 -2)' is a point and '(1 - 2)' is the number -1.
+`
+
+// tolerance usage string.
+const tolerance = `
+Tolerance:
+	- -epsilon e: Numbers at most e apart are equal. Must be more than 0.
+	  Defaults to 1e-7.
+	- -relative r: Numbers at most r times the larger of their sizes apart
+	  are also equal. Lines are also parallel when the sine of the angle
+	  between them is at most r and perpendicular when the cosine is. Must
+	  be more than 0. Isn't used unless it's set.
+`
+
+// angles usage string.
+const angles = `
+Angles:
+	- -angle unit: Prints angles in radians if unit is rad, in degrees like
+	  '90deg' if it's deg, and in multiples of pi like '0.5pi' if it's pi.
+	  Defaults to rad.
+`
+
+// formats usage string.
+const formats = `
+Formats:`
+
+// inputUsage is the usage string of the flag added by InputFlag.
+const inputUsage = `
+	- -input f: Reads transformations as described above if f is text, as
+	  JSON if it's json, as the value of an SVG transform attribute if it's
+	  svg, and as the value of a CSS transform property if it's css.
+	  Defaults to text.`
+
+// outputUsage is the usage string of the flag added by OutputFlag.
+const outputUsage = `
+	- -output f: Writes transformations as described above if f is text,
+	  as JSON if it's json, as the value of an SVG transform attribute if
+	  it's svg, and as the value of a CSS transform property if it's css.
+	  Defaults to text.`
+
+// formatDetails is the usage string describing each Format.
+const formatDetails = `
+
+	JSON transformations are an object or an array of objects to compose.
+	Each object is a line to reflect across like
+	'{"a": {"x": 0, "y": 0}, "b": {"x": 1, "y": 0}}' or has a "type" of
+	"NoTransformation", "LineReflection" with a "line", "Translation" with
+	a "vector" like '{"i": 1, "j": 2}', "Rotation" with a "center" point and
+	an "angle" in radians, or "GlideReflection" with an "axis" line and a
+	"vector". Written transformations are one of these typed objects.
+
+	SVG transforms are lists like 'translate(10 20) rotate(45 5 5)' of
+	matrix, translate, scale, rotate, skewX, and skewY done from last to
+	first. CSS transforms are lists like 'translate(10px, 20px)
+	rotate(45deg)' of the 2D CSS transform functions with lengths in px
+	and a transform-origin of (0 0). Each function must keep distances the
+	same so only scales by 1 and -1 and skews by 0 are allowed. Distances
+	only need to be kept within 1e-3 or -epsilon if it's larger so
+	matrices can be written with 3 digits like 'matrix(0.707 0.707 -0.707
+	0.707 0 0)'. The whole attribute like 'transform="rotate(45)"' or
+	declaration like 'transform: rotate(45deg);' can also be read.
+	Coordinates are used as they are even though the y-axis points down in
+	SVG and CSS. Written numbers are rounded to the fewest digits within
+	the tolerance so 'rotate(90 1 2)' is written back the same.
 `
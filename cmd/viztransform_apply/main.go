@@ -3,35 +3,272 @@
 package main
 
 import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"math"
 	"os"
+	"strings"
 
 	"github.com/jwowillo/viztransform/cmd"
+	"github.com/jwowillo/viztransform/geometry"
 	"github.com/jwowillo/viztransform/parse"
 	"github.com/jwowillo/viztransform/transform"
 )
 
-// main applies the transform.Transformation read from STDIN to the
-// geometry.Point in the args.
+// main applies the transform.Transformation to the geometry.Point in the args
+// or to every geometry.Point read from STDIN if no geometry.Point is passed.
 func main() {
-	if len(os.Args) != 2 {
+	if *file != "" && *text != "" {
+		cmd.Fail(errSource)
+	}
+	if *output != cmd.Text && *output != cmd.JSON {
+		cmd.Fail(errOutput)
+	}
+	if *isCSV && (*input == cmd.JSON || *output == cmd.JSON) {
+		cmd.Fail(errCSVFormat)
+	}
+	switch flag.NArg() {
+	case 0:
+		if *file == "" && *text == "" {
+			cmd.Fail(errSource)
+		}
+		t := transformation()
+		if err := stream(transform.ToMatrix(t)); err != nil {
+			cmd.FailIn("stdin", err)
+		}
+	case 1:
+		if *isCSV {
+			cmd.Fail(errCSV)
+		}
+		p, err := point(flag.Arg(0))
+		if err != nil {
+			cmd.Fail(err)
+		}
+		q, err := transform.ApplyWithinE(transformation(), p, *tol)
+		if err != nil {
+			cmd.Fail(err)
+		}
+		if err := writePoint(os.Stdout, q); err != nil {
+			cmd.Fail(err)
+		}
+	default:
 		cmd.Fail(errArgs)
 	}
-	arg := os.Args[1]
-	p, err := parse.Point(arg)
-	if err != nil {
-		cmd.Fail(err)
+}
+
+// transformation returns the transform.Transformation read from the file,
+// the flag, or STDIN if neither are set.
+func transformation() transform.Transformation {
+	if *file != "" {
+		t, err := input.ReadFile(*file, *tol)
+		if err != nil {
+			cmd.FailIn(*file, err)
+		}
+		return t
 	}
-	t, err := parse.Transformation(os.Stdin)
+	var r io.Reader = os.Stdin
+	name := "stdin"
+	if *text != "" {
+		r = strings.NewReader(*text)
+		name = "-transform"
+	}
+	t, err := input.Read(r, *tol)
 	if err != nil {
-		cmd.Fail(err)
+		cmd.FailIn(name, err)
+	}
+	return t
+}
+
+// point parses the geometry.Point x in the input format.
+func point(x string) (geometry.Point, error) {
+	if *input == cmd.JSON {
+		return parse.JSONPoint(x)
+	}
+	return parse.Point(x)
+}
+
+// writePoint writes geometry.Point p in the output format to w followed by a
+// newline.
+//
+// The text format rounds like the String-representation unless the precise
+// flag is set.
+func writePoint(w io.Writer, p geometry.Point) error {
+	if *output == cmd.JSON {
+		bs, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", bs)
+		return err
+	}
+	_, err := fmt.Fprintf(w, "(%s %s)\n", number(p.X), number(p.Y))
+	return err
+}
+
+// number returns geometry.Number x like its String-representation or with
+// every digit it takes to read it back exactly if the precise flag is set.
+func number(x geometry.Number) string {
+	if *precise {
+		return parse.FormatNumber(x)
+	}
+	return x.String()
+}
+
+// stream every geometry.Point read from STDIN through Matrix m to STDOUT.
+//
+// The geometry.Points are read and written as CSV if the CSV flag is set and
+// one per line in the input and output formats otherwise. Returns a
+// parse.Error on the line of STDIN if a geometry.Point can't be read or is
+// moved to a geometry.Point that isn't finite.
+func stream(m transform.Matrix) error {
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+	if *isCSV {
+		return streamCSV(m, csv.NewReader(os.Stdin), csv.NewWriter(w))
+	}
+	scanner := bufio.NewScanner(os.Stdin)
+	for n := 1; scanner.Scan(); n++ {
+		x := strings.TrimSpace(scanner.Text())
+		if x == "" {
+			continue
+		}
+		p, err := point(x)
+		if err != nil {
+			return onLine(err, n, 0, x)
+		}
+		q := transform.ApplyMatrix(m, p)
+		if !isFinite(q) {
+			return notFinite(n, x)
+		}
+		if err := writePoint(w, q); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// streamCSV streams every geometry.Point read as an 'x,y' record from r
+// through Matrix m to w.
+func streamCSV(m transform.Matrix, r *csv.Reader, w *csv.Writer) error {
+	r.FieldsPerRecord = 2
+	r.TrimLeadingSpace = true
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		line, _ := r.FieldPos(0)
+		snippet := strings.Join(record, ",")
+		x, err := parse.Number(strings.TrimSpace(record[0]))
+		if err != nil {
+			return onLine(err, line, 0, snippet)
+		}
+		y, err := parse.Number(strings.TrimSpace(record[1]))
+		if err != nil {
+			return onLine(err, line, len(record[0])+1, snippet)
+		}
+		q := transform.ApplyMatrix(m, geometry.Point{X: x, Y: y})
+		if !isFinite(q) {
+			return notFinite(line, snippet)
+		}
+		if err := w.Write([]string{number(q.X), number(q.Y)}); err != nil {
+			return err
+		}
 	}
-	fmt.Println(transform.Apply(t, p))
+	w.Flush()
+	return w.Error()
 }
 
-// errArgs is the error when not exactly a single geometry.Point is passed.
-var errArgs = errors.New("must pass point to transform")
+// isFinite returns true if neither coordinate of geometry.Point p is infinite
+// or NaN.
+func isFinite(p geometry.Point) bool {
+	for _, x := range []geometry.Number{p.X, p.Y} {
+		if math.IsInf(float64(x), 0) || math.IsNaN(float64(x)) {
+			return false
+		}
+	}
+	return true
+}
+
+// notFinite returns a parse.Error caused by errNotFinite on line n of STDIN
+// with snippet as the line.
+func notFinite(n int, snippet string) error {
+	return &parse.Error{
+		Line:    n,
+		Column:  1,
+		Snippet: snippet,
+		Hint:    hintFinite,
+		Err:     errNotFinite,
+	}
+}
+
+// onLine moves err to line n of STDIN shifted right by shift characters with
+// snippet as the line if err is a parse.Error.
+func onLine(err error, n, shift int, snippet string) error {
+	if e, ok := err.(*parse.Error); ok {
+		e.Line, e.Column, e.Snippet = n, e.Column+shift, snippet
+	}
+	return err
+}
+
+var (
+	// errArgs is the error when more than a single geometry.Point is
+	// passed.
+	errArgs = errors.New("must pass at most one point to transform")
+	// errSource is the error when both transformation flags are set or
+	// neither are set while streaming points.
+	errSource = errors.New(
+		"must set exactly one of -file or -transform to stream points",
+	)
+	// errCSV is the error when the CSV flag is set without streaming
+	// points.
+	errCSV = errors.New("-csv only applies when streaming points")
+	// errCSVFormat is the error when the CSV flag is set with the JSON
+	// format as the input or output format.
+	errCSVFormat = errors.New("-csv can't be used with JSON")
+	// errOutput is the error when the output format isn't one points can be
+	// written in.
+	errOutput = errors.New("-output must be text or json")
+	// errNotFinite is the error when a streamed geometry.Point is moved to a
+	// geometry.Point that isn't finite.
+	errNotFinite = errors.New("transformed point isn't finite")
+)
+
+// hintFinite is the hint for errNotFinite.
+const hintFinite = "points and where they're moved to must have coordinates " +
+	"between about -1.8e308 and 1.8e308"
+
+var (
+	// file to read the transform.Transformation from.
+	file = flag.String("file", "", "file to read the transformation from")
+	// text of the transform.Transformation.
+	text = flag.String("transform", "", "transformation to apply")
+	// isCSV is true if streamed geometry.Points are CSV.
+	isCSV = flag.Bool("csv", false, "stream points as x,y CSV-records")
+	// precise is true if numbers are written without rounding.
+	precise = flag.Bool(
+		"precise",
+		false,
+		"write numbers with every digit it takes to read them back exactly",
+	)
+)
+
+// tol is the geometry.Tolerance numbers are compared within.
+var tol = cmd.Tolerance()
+
+// input is the format transformations and geometry.Points are read in.
+var input = cmd.InputFlag()
+
+// output is the format geometry.Points are written in.
+var output = cmd.OutputFlag()
 
 // init the command.
 func init() {
@@ -41,8 +278,32 @@ func init() {
 // usage to print.
 const usage = `viztransform_apply usage:
 
-	viztransform_apply '(x y)'
+	viztransform_apply [-epsilon e] [-relative r] [-input f] [-output f]
+		[-precise] '(x y)'
+	viztransform_apply [-epsilon e] [-relative r] [-input f] [-output f]
+		[-precise] (-file path | -transform 'transformation') ['(x y)']
+	viztransform_apply [-epsilon e] [-relative r] [-input f] [-output f]
+		[-precise] [-csv] (-file path | -transform 'transformation')
 
 	The passed point will be transformed by a transformation read from
 	STDIN as a newline-separated and EOF-terminated list of transformations
-	to be composed.`
+	to be composed.
+
+	The transformation can instead be read from the file at path or from
+	the flag with the same format. If it is and no point is passed, points
+	are streamed from STDIN to STDOUT with each point read transformed and
+	written on its own line. Points look like '(x y)' and blank lines are
+	skipped unless -csv is set, in which case each point is an 'x,y'
+	CSV-record. The transformation is turned into a matrix once so large
+	numbers of points can be streamed quickly.
+
+	Numbers are written rounded like '(-1 0)' unless -precise is set, in
+	which case they're written with as many digits as it takes to read
+	them back exactly.
+
+	If -input is json, the transformation is read as JSON and points are
+	read as JSON-objects like '{"x": 1, "y": 2}' with one per line when
+	streaming. If -input is svg or css, only the transformation is read in
+	that format. Points are written as JSON-objects if -output is json and
+	-output can't be svg or css. -csv can't be used with either set to
+	json.`
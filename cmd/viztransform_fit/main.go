@@ -0,0 +1,83 @@
+// Package main fits a transform.Transformation to geometry.Point-pairs with
+// more documentation from the help flag.
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jwowillo/viztransform/cmd"
+	"github.com/jwowillo/viztransform/geometry"
+	"github.com/jwowillo/viztransform/parse"
+	"github.com/jwowillo/viztransform/transform"
+)
+
+// main fits a transform.Transformation to the geometry.Point-pairs read from
+// STDIN and prints it to STDOUT and the residual to STDERR.
+func main() {
+	if flag.NArg() != 0 {
+		cmd.Fail(errArgs)
+	}
+	var src, dst []geometry.Point
+	scanner := bufio.NewScanner(os.Stdin)
+	for n := 1; scanner.Scan(); n++ {
+		x := scanner.Text()
+		if strings.TrimSpace(x) == "" {
+			continue
+		}
+		a, b, err := parse.PointPair(x)
+		if e, ok := err.(*parse.Error); ok {
+			e.Line = n
+		}
+		if err != nil {
+			cmd.FailIn("stdin", err)
+		}
+		src, dst = append(src, a), append(dst, b)
+	}
+	if scanner.Err() != nil {
+		cmd.Fail(scanner.Err())
+	}
+	t, residual, err := transform.FitWithin(src, dst, *tol)
+	if err != nil {
+		cmd.Fail(err)
+	}
+	s, err := transform.SimplifyWithinE(t, *tol)
+	if err != nil {
+		cmd.Fail(err)
+	}
+	if err := output.Println(s, *tol, geometry.Radians); err != nil {
+		cmd.Fail(err)
+	}
+	fmt.Fprintf(os.Stderr, "residual: %s\n", residual)
+}
+
+// errArgs is the error when any arguments are passed.
+var errArgs = errors.New("must not pass any args")
+
+// tol is the geometry.Tolerance numbers are compared within.
+var tol = cmd.Tolerance()
+
+// output is the format the transform.Transformation is written in.
+var output = cmd.OutputFlag()
+
+// init the command.
+func init() {
+	cmd.Init(usage)
+}
+
+// usage to print.
+const usage = `viztransform_fit usage:
+
+	viztransform_fit [-epsilon e] [-relative r] [-output f]
+
+	The point-pairs read from STDIN as a newline-separated and
+	EOF-terminated list of '(x y) (x y)' pairs will be fit by the
+	transformation that best maps the first point of each pair to the
+	second in least squares. Blank lines are skipped. The transformation is
+	printed to STDOUT in the format set by -output and the root-mean-square
+	distance between the mapped first points and the second points is
+	printed to STDERR.`
@@ -0,0 +1,95 @@
+// Package main formats files of transform.Transformations with more
+// documentation from the help flag.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/jwowillo/viztransform/cmd"
+	"github.com/jwowillo/viztransform/parse"
+)
+
+// main formats the files in the args or STDIN if no files are passed.
+func main() {
+	if flag.NArg() == 0 {
+		if *write {
+			cmd.Fail(errWrite)
+		}
+		f, err := parse.ParseFile(os.Stdin)
+		if err != nil {
+			cmd.FailIn("stdin", err)
+		}
+		if err := parse.FormatFile(os.Stdout, f); err != nil {
+			cmd.Fail(err)
+		}
+		return
+	}
+	for _, path := range flag.Args() {
+		if err := format(path); err != nil {
+			cmd.FailIn(path, err)
+		}
+	}
+}
+
+// format the file at path to STDOUT or back to the file if the write flag is
+// set.
+//
+// The file is only written if formatting changed it.
+func format(path string) error {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	f, err := parse.ParseFile(bytes.NewReader(bs))
+	if err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	if err := parse.FormatFile(&b, f); err != nil {
+		return err
+	}
+	if !*write {
+		_, err := io.Copy(os.Stdout, &b)
+		return err
+	}
+	if bytes.Equal(bs, b.Bytes()) {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b.Bytes(), info.Mode().Perm())
+}
+
+// errWrite is the error when the write flag is set without any files.
+var errWrite = errors.New("-w only applies to files")
+
+// write is true if files are formatted in place.
+var write = flag.Bool("w", false, "write the formatted files in place")
+
+// init the command.
+func init() {
+	cmd.Init(usage)
+}
+
+// usage to print.
+const usage = `viztransform_fmt usage:
+
+	viztransform_fmt [-w] [path ...]
+
+	Each file at path holding a newline-separated and EOF-terminated list
+	of transformations will be formatted and written to STDOUT or back to
+	the file in place if -w is set. The transformations are read from STDIN
+	if no paths are passed.
+
+	Formatting keeps every transformation, number value, and comment but
+	puts each top-level transformation on its own line, puts a single space
+	after each ',' and between the parts of lines, points, and vectors and
+	before a '-' sign after an operator like '1- -2', writes numbers
+	without '+' signs or unneeded zeros, and puts each argument of a
+	transformation split over lines on its own line indented by a tab.`
@@ -0,0 +1,64 @@
+// Package main inverts a transform.Transformation with more documentation from
+// the help flag.
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+
+	"github.com/jwowillo/viztransform/cmd"
+	"github.com/jwowillo/viztransform/transform"
+)
+
+// main inverts the transform.Transformation read from STDIN.
+func main() {
+	if flag.NArg() != 0 {
+		cmd.Fail(errArgs)
+	}
+	t, err := input.Read(os.Stdin, *tol)
+	if err != nil {
+		cmd.FailIn("stdin", err)
+	}
+	s, err := transform.SimplifyWithinE(t, *tol)
+	if err != nil {
+		cmd.Fail(err)
+	}
+	i, err := transform.InverseWithinE(s, *tol)
+	if err != nil {
+		cmd.Fail(err)
+	}
+	if err := output.Println(i, *tol, *unit); err != nil {
+		cmd.Fail(err)
+	}
+}
+
+// errArgs is the error when any arguments are passed.
+var errArgs = errors.New("must not pass any args")
+
+// tol is the geometry.Tolerance numbers are compared within.
+var tol = cmd.Tolerance()
+
+// unit is the geometry.AngleUnit angles are printed in.
+var unit = cmd.AngleUnit()
+
+// input is the format transformations are read in.
+var input = cmd.InputFlag()
+
+// output is the format transformations are written in.
+var output = cmd.OutputFlag()
+
+// init the command.
+func init() {
+	cmd.Init(usage)
+}
+
+// usage to print.
+const usage = `viztransform_inverse usage:
+
+	viztransform_inverse [-epsilon e] [-relative r] [-angle unit] [-input f]
+		[-output f]
+
+	The transformation read from STDIN as a newline-separated and
+	EOF-terminated list of transformations to be composed will be inverted
+	into the single transformation that undoes it.`
@@ -4,27 +4,73 @@ package main
 
 import (
 	"errors"
+	"flag"
 	"fmt"
 	"os"
 
 	"github.com/jwowillo/viztransform/cmd"
+	"github.com/jwowillo/viztransform/exact"
 	"github.com/jwowillo/viztransform/parse"
+	"github.com/jwowillo/viztransform/transform"
 )
 
 // main simplifies the transform.Transformation read from STDIN.
+//
+// The exact.Transformation read from STDIN is simplified instead if the exact
+// flag is passed.
 func main() {
-	if len(os.Args) != 1 {
+	if flag.NArg() != 0 {
 		cmd.Fail(errArgs)
 	}
-	t, err := parse.Transformation(os.Stdin)
+	if *isExact {
+		if *input != cmd.Text || *output != cmd.Text {
+			cmd.Fail(errExactFormat)
+		}
+		t, err := parse.ExactTransformation(os.Stdin)
+		if err != nil {
+			cmd.FailIn("stdin", err)
+		}
+		fmt.Println(exact.Simplify(t).StringIn(*unit))
+		return
+	}
+	t, err := input.Read(os.Stdin, *tol)
+	if err != nil {
+		cmd.FailIn("stdin", err)
+	}
+	s, err := transform.SimplifyWithinE(t, *tol)
 	if err != nil {
 		cmd.Fail(err)
 	}
-	fmt.Println(t)
+	if err := output.Println(s, *tol, *unit); err != nil {
+		cmd.Fail(err)
+	}
 }
 
-// errArgs is the error when any arguments are passed.
-var errArgs = errors.New("must not pass any args")
+var (
+	// errArgs is the error when any arguments are passed.
+	errArgs = errors.New("must not pass any args")
+	// errExactFormat is the error when the exact flag is set with a format
+	// other than text.
+	errExactFormat = errors.New(
+		"-exact only applies to -input text and -output text",
+	)
+)
+
+// isExact is true if the transformation should be simplified with exact
+// rational arithmetic.
+var isExact = flag.Bool("exact", false, "simplify with exact rational arithmetic")
+
+// tol is the geometry.Tolerance numbers are compared within.
+var tol = cmd.Tolerance()
+
+// unit is the geometry.AngleUnit angles are printed in.
+var unit = cmd.AngleUnit()
+
+// input is the format transformations are read in.
+var input = cmd.InputFlag()
+
+// output is the format transformations are written in.
+var output = cmd.OutputFlag()
 
 // init the command.
 func init() {
@@ -34,8 +80,14 @@ func init() {
 // usage to print.
 const usage = `viztransform_simplify usage:
 
-	viztransform_simplify
+	viztransform_simplify [-exact] [-epsilon e] [-relative r] [-angle unit]
+		[-input f] [-output f]
 
 	The transformation read from STDIN as a newline-separated and
 	EOF-terminated list of transformations to be composed will be simplified
-	into a single transformation.`
+	into a single transformation.
+
+	If -exact is passed, numbers like 1/3 are worked with exactly instead of
+	being rounded and can't use constants outside of angles. Rotations must
+	then be by a multiple of a quarter turn and the tolerance is ignored.
+	-exact can only be used with -input text and -output text.`
@@ -3,37 +3,86 @@
 package main
 
 import (
+	"bytes"
 	"errors"
+	"flag"
+	"image/gif"
 	"image/png"
+	"io"
 	"os"
 
 	"github.com/jwowillo/viztransform/cmd"
-	"github.com/jwowillo/viztransform/parse"
+	"github.com/jwowillo/viztransform/transform"
 	"github.com/jwowillo/viztransform/viz"
 )
 
 // main outputs a vizualization for the transform.Transfromation read from
 // STDIN.
 func main() {
-	if len(os.Args) != 2 {
+	if flag.NArg() != 1 {
 		cmd.Fail(errArgs)
 	}
-	f, err := os.OpenFile(os.Args[1]+".png", os.O_CREATE|os.O_TRUNC, 0777)
+	if *format != "png" && *format != "svg" && *format != "gif" {
+		cmd.Fail(errFormat)
+	}
+	t, err := input.Read(os.Stdin, *tol)
 	if err != nil {
-		cmd.Fail(err)
+		cmd.FailIn("stdin", err)
+	}
+	var b bytes.Buffer
+	switch *format {
+	case "png":
+		err = encodePNG(&b, t)
+	case "svg":
+		err = viz.SVGWithin(&b, t, *tol)
+	case "gif":
+		err = encodeGIF(&b, t)
 	}
-	defer f.Close()
-	t, err := parse.Transformation(os.Stdin)
 	if err != nil {
 		cmd.Fail(err)
 	}
-	if err := png.Encode(f, viz.Transformation(t)); err != nil {
+	// The vizualization is only written once it's made so a failure doesn't
+	// leave an empty file.
+	path := flag.Arg(0) + "." + *format
+	if err := os.WriteFile(path, b.Bytes(), 0644); err != nil {
 		cmd.Fail(err)
 	}
 }
 
-// errArgs is the error when not a single output-file is passed.
-var errArgs = errors.New("must pass output-file")
+// encodePNG writes the vizualization of transform.Transformation t to w as a
+// PNG.
+func encodePNG(w io.Writer, t transform.Transformation) error {
+	img, err := viz.TransformationWithin(t, *tol)
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, img)
+}
+
+// encodeGIF writes the animation of transform.Transformation t to w as a GIF.
+func encodeGIF(w io.Writer, t transform.Transformation) error {
+	g, err := viz.AnimationWithin(t, *tol)
+	if err != nil {
+		return err
+	}
+	return gif.EncodeAll(w, g)
+}
+
+var (
+	// errArgs is the error when not a single output-file is passed.
+	errArgs = errors.New("must pass output-file")
+	// errFormat is the error when the format isn't recognized.
+	errFormat = errors.New("format must be png, svg, or gif")
+)
+
+// format of the vizualization.
+var format = flag.String("format", "png", "format of the vizualization")
+
+// input is the format the transformation is read in.
+var input = cmd.InputFlag()
+
+// tol is the geometry.Tolerance numbers are compared within.
+var tol = cmd.Tolerance()
 
 // init the command.
 func init() {
@@ -42,11 +91,17 @@ func init() {
 
 const usage = `viztransform_viz usage:
 
-	viztransform_viz
+	viztransform_viz [-format png|svg|gif] [-input f] [-epsilon e]
+		[-relative r] output-file
 
 	A vizualization of the transformation read from STDIN as a
 	newline-separated and EOF-terimanted list of transformations to be
 	composed. The vizualization will consist of 1 panel demonstrating the
 	transformation if the transformation is already simplified and 2 panels
 	demonstrating the transformation and the simplified transformation
-	otherwise.`
+	otherwise. The vizualization is written to output-file with the format
+	appended as an extension. The format is png by default and can be set to
+	svg for a vector image or gif for an animation that moves a figure
+	through each line-reflection in turn and then through the simplified
+	transformation. The transformation can be read in another format with
+	-input.`
@@ -0,0 +1,79 @@
+package exact
+
+import (
+	"math/big"
+)
+
+// Intersection returns the Point where Lines a and b intersect.
+//
+// Returns ErrNoIntersection if the Lines are parallel since the intersection
+// won't exist if the Lines aren't the same or occurs at infinitely many Points
+// if the Lines are the same.
+func Intersection(a, b Line) (Point, error) {
+	if AreParallel(a, b) {
+		return Point{}, ErrNoIntersection
+	}
+	d := det(a.a, a.b, b.a, b.b)
+	return Point{
+		X: quo(det(a.c, a.b, b.c, b.b), d),
+		Y: quo(det(a.a, a.c, b.a, b.c), d),
+	}, nil
+}
+
+// Reflect Point p across Line l.
+func Reflect(l Line, p Point) Point {
+	n := add(mul(l.a, l.a), mul(l.b, l.b))
+	k := quo(mul(two, sub(add(mul(l.a, p.X), mul(l.b, p.Y)), l.c)), n)
+	return Point{X: sub(p.X, mul(k, l.a)), Y: sub(p.Y, mul(k, l.b))}
+}
+
+// Normal returns a Vector perpendicular to Line l.
+func Normal(l Line) Vector {
+	return Vector{I: l.a, J: l.b}
+}
+
+// Dot is the dot-product of Vectors a and b.
+func Dot(a, b Vector) Number {
+	return add(mul(a.I, b.I), mul(a.J, b.J))
+}
+
+// two is the Number 2.
+var two = NewNumber(2, 1)
+
+// det is determinant of 2x2 matrix with a and b in the first row and c and d in
+// the second row.
+func det(a, b, c, d Number) Number {
+	return sub(mul(a, d), mul(b, c))
+}
+
+// isZero returns true if Number n is 0.
+func isZero(n Number) bool {
+	return n.rat().Sign() == 0
+}
+
+// add returns a + b.
+func add(a, b Number) Number {
+	return Number{r: new(big.Rat).Add(a.rat(), b.rat())}
+}
+
+// sub returns a - b.
+func sub(a, b Number) Number {
+	return Number{r: new(big.Rat).Sub(a.rat(), b.rat())}
+}
+
+// mul returns a * b.
+func mul(a, b Number) Number {
+	return Number{r: new(big.Rat).Mul(a.rat(), b.rat())}
+}
+
+// quo returns a / b.
+//
+// Panics if b is 0.
+func quo(a, b Number) Number {
+	return Number{r: new(big.Rat).Quo(a.rat(), b.rat())}
+}
+
+// neg returns -n.
+func neg(n Number) Number {
+	return Number{r: new(big.Rat).Neg(n.rat())}
+}
@@ -0,0 +1,32 @@
+package exact
+
+// IsZero returns true if Number n is 0.
+func IsZero(n Number) bool {
+	return isZero(n)
+}
+
+// AreEqual returns true if Numbers a and b are the same.
+func AreEqual(a, b Number) bool {
+	return a.rat().Cmp(b.rat()) == 0
+}
+
+// AreSamePoint returns true if Points a and b are the same.
+func AreSamePoint(a, b Point) bool {
+	return AreEqual(a.X, b.X) && AreEqual(a.Y, b.Y)
+}
+
+// AreParallel returns true if Lines a and b are parallel.
+func AreParallel(a, b Line) bool {
+	return isZero(det(a.a, a.b, b.a, b.b))
+}
+
+// ArePerpendicular returns true if Lines a and b are perpendicular.
+func ArePerpendicular(a, b Line) bool {
+	return isZero(add(mul(a.a, b.a), mul(a.b, b.b)))
+}
+
+// AreSameLine return true if Lines a and b are the same.
+func AreSameLine(a, b Line) bool {
+	return AreParallel(a, b) &&
+		isZero(det(a.a, a.c, b.a, b.c)) && isZero(det(a.b, a.c, b.b, b.c))
+}
@@ -0,0 +1,322 @@
+package exact
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/jwowillo/viztransform/geometry"
+	"github.com/jwowillo/viztransform/transform"
+)
+
+// Transformation is a list of Lines each representing a line-reflection that
+// are all composed together as described by transform.Transformation.
+type Transformation []Line
+
+// Apply the Transformation to the Point by applying each line-reflection
+// making up the Transformation in order.
+func Apply(t Transformation, p Point) Point {
+	for _, l := range t {
+		p = Reflect(l, p)
+	}
+	return p
+}
+
+// Compose Transformations into a single Transformation which is the
+// line-reflections in each Transformation appended together in order.
+func Compose(ts ...Transformation) Transformation {
+	var composed Transformation
+	for _, t := range ts {
+		composed = append(composed, t...)
+	}
+	return composed
+}
+
+// Inverse of Transformation t which undoes t.
+//
+// Is the line-reflections making up t in reverse order simplified since each
+// line-reflection undoes itself.
+func Inverse(t Transformation) Transformation {
+	inverse := make(Transformation, len(t))
+	for i, l := range t {
+		inverse[len(t)-1-i] = l
+	}
+	return Simplify(inverse)
+}
+
+// Power of Transformation t which is t composed with itself n times as
+// described by transform.Power.
+func Power(t Transformation, n int) Transformation {
+	if n < 0 {
+		t, n = Inverse(t), -n
+	}
+	t = Simplify(t)
+	p := NoTransformation()
+	for ; n > 0; n /= 2 {
+		if n%2 == 1 {
+			p = Simplify(Compose(p, t))
+		}
+		t = Simplify(Compose(t, t))
+	}
+	return p
+}
+
+// Conjugate of Transformation a by Transformation b which undoes b, does a,
+// and then does b as described by transform.Conjugate.
+func Conjugate(a, b Transformation) Transformation {
+	return Compose(Inverse(b), a, b)
+}
+
+// NoTransformation is a Transformation-constructor that creates a
+// Transformation with transform.TypeNoTransformation.
+func NoTransformation() Transformation {
+	return Transformation{}
+}
+
+// LineReflection is a Transformation-constructor that creates a Transformation
+// with transform.TypeLineReflection that reflects Points across Line l.
+func LineReflection(l Line) Transformation {
+	return Transformation{l}
+}
+
+// Translation is a Transformation-constructor that creates a Transformation
+// with transform.TypeTranslation that translates Points by Vector v.
+//
+// Returns NoTransformation() if v is length 0.
+func Translation(v Vector) Transformation {
+	if isZero(v.I) && isZero(v.J) {
+		return NoTransformation()
+	}
+	half := Point{X: quo(v.I, two), Y: quo(v.J, two)}
+	a, _ := NewLineFromPointAndNormal(Point{}, v)
+	b, _ := NewLineFromPointAndNormal(half, v)
+	return Transformation{a, b}
+}
+
+// Rotation is a Transformation-constructor that creates a Transformation with
+// transform.TypeRotation that rotates Points counter-clockwise around Point p
+// by the angle with cosine cos and sine sin.
+//
+// Returns NoTransformation() if the angle is 0. Returns ErrNotRotation if cos
+// squared plus sin squared isn't 1.
+func Rotation(p Point, cos, sin Number) (Transformation, error) {
+	one := NewNumber(1, 1)
+	if !AreEqual(add(mul(cos, cos), mul(sin, sin)), one) {
+		return nil, ErrNotRotation
+	}
+	if AreEqual(cos, one) {
+		return NoTransformation(), nil
+	}
+	a, _ := NewLineFromPointAndNormal(p, Vector{I: Number{}, J: one})
+	b, _ := NewLineFromPointAndNormal(p, halfNormal(cos, sin))
+	return Transformation{a, b}, nil
+}
+
+// GlideReflection is a Transformation-constructor that creates a
+// Transformation with transform.TypeGlideReflection that reflects Points across
+// Line ref and translates them by the projection of Vector v onto ref.
+//
+// Returns LineReflection(ref) if the projection is length 0.
+func GlideReflection(ref Line, v Vector) Transformation {
+	n := Normal(ref)
+	k := quo(Dot(v, n), Dot(n, n))
+	along := Vector{I: sub(v.I, mul(k, n.I)), J: sub(v.J, mul(k, n.J))}
+	return Compose(Translation(along), LineReflection(ref))
+}
+
+// IsSimplified returns true if the Transformation t uses the minimum number of
+// line-reflections to achieve the result of the Transformation.
+func IsSimplified(t Transformation) bool {
+	switch len(t) {
+	case 0, 1:
+		return true
+	case 2:
+		return !AreSameLine(t[0], t[1])
+	case 3:
+		a, b, c := t[0], t[1], t[2]
+		return ArePerpendicular(a, b) &&
+			AreParallel(b, c) && !AreSameLine(b, c) ||
+			AreParallel(a, b) && !AreSameLine(a, b) &&
+				ArePerpendicular(b, c)
+	}
+	return false
+}
+
+// Simplify Transformation t into its simplest form that expresses the same
+// Transformation.
+//
+// Unlike transform.Simplify, the line-reflections are composed into an affine
+// map which is then split back into line-reflections. Every step only adds,
+// subtracts, multiplies, and divides so no rounding happens.
+func Simplify(t Transformation) Transformation {
+	if IsSimplified(t) {
+		return t
+	}
+	m := identity()
+	for _, l := range t {
+		m = multiply(reflection(l), m)
+	}
+	return fromAffine(m)
+}
+
+// TypeOf a Transformation from the transform.Transformation-Types.
+func TypeOf(t Transformation) transform.Type {
+	t = Simplify(t)
+	if len(t) == 0 {
+		return transform.TypeNoTransformation
+	} else if len(t) == 1 {
+		return transform.TypeLineReflection
+	} else if len(t) == 2 && AreParallel(t[0], t[1]) {
+		return transform.TypeTranslation
+	} else if len(t) == 2 {
+		return transform.TypeRotation
+	}
+	return transform.TypeGlideReflection
+}
+
+// Float returns the transform.Transformation closest to the Transformation.
+func Float(t Transformation) transform.Transformation {
+	out := make(transform.Transformation, len(t))
+	for i, l := range t {
+		out[i] = l.Float()
+	}
+	return out
+}
+
+// String-representation of the Transformation.
+//
+// Looks like the string-representation of a transform.Transformation with
+// exact Numbers everywhere except for a rotation's angle which is rounded.
+func (t Transformation) String() string {
+	return t.StringIn(geometry.Radians)
+}
+
+// StringIn returns the String-representation of the Transformation with a
+// rotation's angle in geometry.AngleUnit u.
+func (t Transformation) StringIn(u geometry.AngleUnit) string {
+	t = Simplify(t)
+	switch TypeOf(t) {
+	case transform.TypeLineReflection:
+		return fmt.Sprintf("LineReflection(%s)", t[0])
+	case transform.TypeTranslation:
+		return fmt.Sprintf("Translation(%s)", translationVector(t[0], t[1]))
+	case transform.TypeRotation:
+		m := multiply(reflection(t[1]), reflection(t[0]))
+		c := mustPoint(Intersection(t[0], t[1]))
+		rads := geometry.Angle(math.Atan2(
+			float64(m.c.Float()),
+			float64(m.a.Float()),
+		))
+		return fmt.Sprintf("Rotation(%s, %s)", c, rads.In(u))
+	case transform.TypeGlideReflection:
+		a, b, c := t[0], t[1], t[2]
+		if AreParallel(b, c) {
+			a, b, c = b, c, a
+		}
+		return fmt.Sprintf(
+			"GlideReflection(%s, %s)",
+			c, translationVector(a, b),
+		)
+	}
+	return "NoTransformation()"
+}
+
+// translationVector returns the Vector that the parallel Lines a and b
+// translate by, which is 2 times the shortest Vector from a to b.
+func translationVector(a, b Line) Vector {
+	n := Normal(a)
+	// b's normal is scale times a's.
+	var scale Number
+	if isZero(a.a) {
+		scale = quo(b.b, a.b)
+	} else {
+		scale = quo(b.a, a.a)
+	}
+	k := quo(sub(quo(b.c, scale), a.c), Dot(n, n))
+	return Vector{I: mul(two, mul(k, n.I)), J: mul(two, mul(k, n.J))}
+}
+
+// mustPoint panics if err isn't nil and returns the Point otherwise.
+func mustPoint(p Point, err error) Point {
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// halfNormal returns a Vector perpendicular to the direction at half the angle
+// with cosine cos and sine sin.
+//
+// The direction at half the angle is (1+cos, sin) unless the angle is a half
+// turn where the direction is (0, 1).
+func halfNormal(cos, sin Number) Vector {
+	one := NewNumber(1, 1)
+	if AreEqual(cos, neg(one)) {
+		return Vector{I: one, J: Number{}}
+	}
+	return Vector{I: neg(sin), J: add(one, cos)}
+}
+
+// affine is the map (x, y) to (ax + by + e, cx + dy + f).
+type affine struct{ a, b, c, d, e, f Number }
+
+// identity returns the affine that leaves every Point in place.
+func identity() affine {
+	one := NewNumber(1, 1)
+	return affine{a: one, d: one}
+}
+
+// multiply affines m and n into the affine that maps by n and then by m.
+func multiply(m, n affine) affine {
+	return affine{
+		a: add(mul(m.a, n.a), mul(m.b, n.c)),
+		b: add(mul(m.a, n.b), mul(m.b, n.d)),
+		c: add(mul(m.c, n.a), mul(m.d, n.c)),
+		d: add(mul(m.c, n.b), mul(m.d, n.d)),
+		e: add(add(mul(m.a, n.e), mul(m.b, n.f)), m.e),
+		f: add(add(mul(m.c, n.e), mul(m.d, n.f)), m.f),
+	}
+}
+
+// reflection returns the affine that reflects Points across Line l.
+func reflection(l Line) affine {
+	n := add(mul(l.a, l.a), mul(l.b, l.b))
+	k := func(x, y Number) Number { return quo(mul(two, mul(x, y)), n) }
+	one := NewNumber(1, 1)
+	return affine{
+		a: sub(one, k(l.a, l.a)),
+		b: neg(k(l.a, l.b)),
+		c: neg(k(l.a, l.b)),
+		d: sub(one, k(l.b, l.b)),
+		e: k(l.a, l.c),
+		f: k(l.b, l.c),
+	}
+}
+
+// fromAffine returns the simplified Transformation that maps Points the same
+// way as affine m which must be a composition of line-reflections.
+func fromAffine(m affine) Transformation {
+	cos, sin := m.a, m.c
+	v := Vector{I: m.e, J: m.f}
+	if det(m.a, m.b, m.c, m.d).rat().Sign() > 0 {
+		if AreEqual(cos, NewNumber(1, 1)) {
+			return Translation(v)
+		}
+		// The center solves (I - R)c = v.
+		one := NewNumber(1, 1)
+		d := mul(two, sub(one, cos))
+		c := Point{
+			X: quo(sub(mul(sub(one, cos), v.I), mul(sin, v.J)), d),
+			Y: quo(add(mul(sin, v.I), mul(sub(one, cos), v.J)), d),
+		}
+		t, _ := Rotation(c, cos, sin)
+		return t
+	}
+	// The axis is at half the angle and the part of v across the axis puts
+	// the axis at half of that part.
+	n := halfNormal(cos, sin)
+	k := quo(Dot(v, n), Dot(n, n))
+	across := Vector{I: mul(k, n.I), J: mul(k, n.J)}
+	p := Point{X: quo(across.I, two), Y: quo(across.J, two)}
+	axis, _ := NewLineFromPointAndNormal(p, n)
+	return GlideReflection(axis, v)
+}
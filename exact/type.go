@@ -0,0 +1,178 @@
+// Package exact defines exact rational versions of the geometric primitives in
+// package geometry along with a Transformation that can be simplified and
+// classified without any rounding.
+//
+// Every predicate is exact so there is no Epsilon. Rotations are only exact
+// when the cosine and sine of their angle are rational so rotations by other
+// angles must stay in package transform.
+package exact
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/jwowillo/viztransform/geometry"
+)
+
+var (
+	// ErrNoIntersection is returned when parallel Lines are given to
+	// something expecting intersecting Lines.
+	ErrNoIntersection = errors.New("parallel Lines don't intersect")
+	// ErrNoLine is returned when a Line is created with two Points that are
+	// the same.
+	ErrNoLine = errors.New("given information doesn't determine a Line")
+	// ErrNotRotation is returned when a cosine and sine given to something
+	// expecting the cosine and sine of an angle aren't.
+	ErrNotRotation = errors.New("cosine and sine don't determine an angle")
+)
+
+// Number is an exact rational Number.
+//
+// The zero-value is 0. Numbers are never modified after they're created so
+// they can be copied and shared freely.
+type Number struct{ r *big.Rat }
+
+// NewNumber creates the Number a/b.
+//
+// Panics if b is 0.
+func NewNumber(a, b int64) Number {
+	return Number{r: big.NewRat(a, b)}
+}
+
+// NewNumberFromRat creates a Number with the same value as big.Rat r.
+func NewNumberFromRat(r *big.Rat) Number {
+	return Number{r: new(big.Rat).Set(r)}
+}
+
+// NewNumberFromFloat creates a Number with exactly the same value as the
+// geometry.Number n.
+//
+// Returns false if n is infinite or not a number.
+func NewNumberFromFloat(n geometry.Number) (Number, bool) {
+	r := new(big.Rat)
+	if r.SetFloat64(float64(n)) == nil {
+		return Number{}, false
+	}
+	return Number{r: r}, true
+}
+
+// rat returns the big.Rat with the Number's value.
+//
+// The big.Rat must not be modified.
+func (n Number) rat() *big.Rat {
+	if n.r == nil {
+		return new(big.Rat)
+	}
+	return n.r
+}
+
+// Rat returns a big.Rat with the Number's value.
+func (n Number) Rat() *big.Rat {
+	return new(big.Rat).Set(n.rat())
+}
+
+// Float returns the geometry.Number closest to the Number.
+func (n Number) Float() geometry.Number {
+	f, _ := n.rat().Float64()
+	return geometry.Number(f)
+}
+
+// String-representation of the Number.
+//
+// Looks like 'a/b' where a/b is the Number in lowest terms or 'a' if b is 1.
+func (n Number) String() string {
+	return n.rat().RatString()
+}
+
+// Point in the xy-plane defined by x and y coordinates.
+type Point struct{ X, Y Number }
+
+// Float returns the geometry.Point closest to the Point.
+func (p Point) Float() geometry.Point {
+	return geometry.Point{X: p.X.Float(), Y: p.Y.Float()}
+}
+
+// String-representation of the Point.
+//
+// Looks like '(X Y)' where X and Y are the Point's X and Y values.
+func (p Point) String() string {
+	return fmt.Sprintf("(%s %s)", p.X, p.Y)
+}
+
+// Vector is a representation of a direction and a magnitude as described by
+// geometry.Vector.
+type Vector struct{ I, J Number }
+
+// Float returns the geometry.Vector closest to the Vector.
+func (v Vector) Float() geometry.Vector {
+	return geometry.Vector{I: v.I.Float(), J: v.J.Float()}
+}
+
+// String-representation of the Vector.
+//
+// Looks like '<I J>' where I and J are the Vector's I and J values.
+func (v Vector) String() string {
+	return fmt.Sprintf("<%s %s>", v.I, v.J)
+}
+
+// Line is a straight curve through 2 different Points.
+//
+// The Line is stored as the coefficients of its equation ax + by = c so that
+// no Points need to be found to work with it.
+type Line struct{ a, b, c Number }
+
+// NewLineFromPoints creates a Line from 2 Points on the Line.
+//
+// Returns ErrNoLine if both Points are the same.
+func NewLineFromPoints(p, q Point) (Line, error) {
+	if AreSamePoint(p, q) {
+		return Line{}, ErrNoLine
+	}
+	a, b := sub(q.Y, p.Y), sub(p.X, q.X)
+	return Line{a: a, b: b, c: add(mul(a, p.X), mul(b, p.Y))}, nil
+}
+
+// NewLineFromPointAndNormal creates a Line through Point p perpendicular to
+// Vector n.
+//
+// Returns ErrNoLine if n is length 0.
+func NewLineFromPointAndNormal(p Point, n Vector) (Line, error) {
+	if isZero(n.I) && isZero(n.J) {
+		return Line{}, ErrNoLine
+	}
+	return Line{a: n.I, b: n.J, c: add(mul(n.I, p.X), mul(n.J, p.Y))}, nil
+}
+
+// StandardCoefficients for the Line's equation and the value the equation is
+// equal to.
+//
+// Line-equation is ax + by = c.
+func StandardCoefficients(l Line) (Number, Number, Number) {
+	return l.a, l.b, l.c
+}
+
+// Points returns 2 different Points on the Line.
+func (l Line) Points() (Point, Point) {
+	if isZero(l.b) {
+		x := quo(l.c, l.a)
+		return Point{X: x, Y: Number{}}, Point{X: x, Y: NewNumber(1, 1)}
+	}
+	one := NewNumber(1, 1)
+	return Point{X: Number{}, Y: quo(l.c, l.b)},
+		Point{X: one, Y: quo(sub(l.c, l.a), l.b)}
+}
+
+// Float returns the geometry.Line closest to the Line.
+func (l Line) Float() geometry.Line {
+	p, q := l.Points()
+	return geometry.MustLine(geometry.NewLineFromPoints(p.Float(), q.Float()))
+}
+
+// String-representation of the Line.
+//
+// Looks like '{A B}' where A and B are two different Points on the Line.
+func (l Line) String() string {
+	p, q := l.Points()
+	return fmt.Sprintf("{%s %s}", p, q)
+}
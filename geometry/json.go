@@ -0,0 +1,125 @@
+package geometry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrMissingField is returned when a JSON-object is missing a field needed to
+// decode a primitive.
+var ErrMissingField = errors.New("JSON-object is missing a field")
+
+// missing returns ErrMissingField for the field called name.
+func missing(name string) error {
+	return fmt.Errorf("%w '%s'", ErrMissingField, name)
+}
+
+// jsonNumber returns Number n as the float64 it's encoded as in JSON.
+//
+// -0 is returned as 0 so it isn't encoded as '-0'.
+func jsonNumber(n Number) float64 {
+	if n == 0 {
+		return 0
+	}
+	return float64(n)
+}
+
+// MarshalJSON encodes the Point as a JSON-object like '{"x": X, "y": Y}'.
+func (p Point) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		X float64 `json:"x"`
+		Y float64 `json:"y"`
+	}{jsonNumber(p.X), jsonNumber(p.Y)})
+}
+
+// UnmarshalJSON decodes the Point from a JSON-object like '{"x": X, "y": Y}'.
+//
+// Returns ErrMissingField if either field is missing.
+func (p *Point) UnmarshalJSON(bs []byte) error {
+	var v struct {
+		X *float64 `json:"x"`
+		Y *float64 `json:"y"`
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	if v.X == nil {
+		return missing("x")
+	}
+	if v.Y == nil {
+		return missing("y")
+	}
+	*p = Point{X: Number(*v.X), Y: Number(*v.Y)}
+	return nil
+}
+
+// MarshalJSON encodes the Vector as a JSON-object like '{"i": I, "j": J}'.
+func (v Vector) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		I float64 `json:"i"`
+		J float64 `json:"j"`
+	}{jsonNumber(v.I), jsonNumber(v.J)})
+}
+
+// UnmarshalJSON decodes the Vector from a JSON-object like '{"i": I, "j": J}'.
+//
+// Returns ErrMissingField if either field is missing.
+func (v *Vector) UnmarshalJSON(bs []byte) error {
+	var w struct {
+		I *float64 `json:"i"`
+		J *float64 `json:"j"`
+	}
+	if err := json.Unmarshal(bs, &w); err != nil {
+		return err
+	}
+	if w.I == nil {
+		return missing("i")
+	}
+	if w.J == nil {
+		return missing("j")
+	}
+	*v = Vector{I: Number(*w.I), J: Number(*w.J)}
+	return nil
+}
+
+// MarshalJSON encodes the Line as a JSON-object like '{"a": A, "b": B}' where
+// A and B are the JSON-objects of the Points the Line was created from.
+//
+// Unlike the String-representation, the Points aren't rounded so the same Line
+// is decoded.
+func (l Line) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		A Point `json:"a"`
+		B Point `json:"b"`
+	}{l.a, l.b})
+}
+
+// UnmarshalJSON decodes the Line from a JSON-object like '{"a": A, "b": B}'
+// where A and B are the JSON-objects of 2 different Points on the Line.
+//
+// Returns ErrMissingField if either Point is missing and ErrNoLine if the
+// Points are exactly the same. UnmarshalJSON can't know the Tolerance the Line
+// will be used within so callers that need one check the Points from
+// Line.Points with Tolerance.NewLineFromPoints themselves.
+func (l *Line) UnmarshalJSON(bs []byte) error {
+	var v struct {
+		A *Point `json:"a"`
+		B *Point `json:"b"`
+	}
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+	if v.A == nil {
+		return missing("a")
+	}
+	if v.B == nil {
+		return missing("b")
+	}
+	nl, err := Tolerance{}.NewLineFromPoints(*v.A, *v.B)
+	if err != nil {
+		return err
+	}
+	*l = nl
+	return nil
+}
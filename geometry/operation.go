@@ -6,22 +6,18 @@ import (
 
 // Shift the Line by the Vector.
 func Shift(l Line, v Vector) Line {
-	return MustLine(NewLineFromPoints(
-		Point{X: l.a.X + v.I, Y: l.a.Y + v.J},
-		Point{X: l.b.X + v.I, Y: l.b.Y + v.J},
-	))
+	// Shifting keeps the Points different so the Line doesn't need checking.
+	return Line{
+		a: Point{X: l.a.X + v.I, Y: l.a.Y + v.J},
+		b: Point{X: l.b.X + v.I, Y: l.b.Y + v.J},
+	}
 }
 
 // ShortestVector returns the shortest Vector from Lines a to b.
 //
 // The Vector has length 0 if the Lines intersect.
 func ShortestVector(a, b Line) Vector {
-	if !AreParallel(a, b) {
-		return Vector{I: 0, J: 0}
-	}
-	p := Perpendicular(a)
-	m, n := MustPoint(Intersection(a, p)), MustPoint(Intersection(b, p))
-	return Vector{I: n.X - m.X, J: n.Y - m.Y}
+	return DefaultTolerance().ShortestVector(a, b)
 }
 
 // Scale a Vector to a new one of the same direction but given length.
@@ -29,11 +25,7 @@ func ShortestVector(a, b Line) Vector {
 // Returns ErrNoVector if v is length 0 since the scaled Vector's direction
 // can't be determined.
 func Scale(v Vector, l Number) (Vector, error) {
-	cl := Length(v)
-	if IsZero(cl) {
-		return Vector{}, ErrNoVector
-	}
-	return Vector{I: l * v.I / cl, J: l * v.J / cl}, nil
+	return DefaultTolerance().Scale(v, l)
 }
 
 // Length of Vector v.
@@ -49,17 +41,7 @@ func Distance(a, b Point) Number {
 // AngleBetween Lines a and b so that it is the shortest Angle to rotate a
 // counter-clockwise to be parallel to b.
 func AngleBetween(a, b Line) Angle {
-	if AreParallel(a, b) {
-		return 0
-	}
-	radsa := math.Atan2(float64(dy(a)), float64(dx(a)))
-	radsb := math.Atan2(float64(dy(b)), float64(dx(b)))
-	rads := math.Mod(radsa-radsb, 2*math.Pi)
-	i := MustPoint(Intersection(a, b))
-	if !AreParallel(Rotate(a, i, Angle(rads)), b) {
-		rads = 2*math.Pi - rads
-	}
-	return Angle(rads)
+	return DefaultTolerance().AngleBetween(a, b)
 }
 
 // Perpendicular Line to Line l through any Point on l.
@@ -70,7 +52,7 @@ func Perpendicular(l Line) Line {
 // PerpendicularThroughPoint returns a perpendicular Line to Line l that passes
 // through Point p.
 func PerpendicularThroughPoint(l Line, p Point) Line {
-	return MustLine(NewLineFromPointAndSlope(p, -dx(l), dy(l)))
+	return Line{a: p, b: Point{X: p.X + dy(l), Y: p.Y - dx(l)}}
 }
 
 // Intersection returns the Point where Lines a and b intersect.
@@ -80,14 +62,7 @@ func PerpendicularThroughPoint(l Line, p Point) Line {
 // exist if the Lines aren't the same or occurs at infinitely many Points if the
 // Lines are the same.
 func Intersection(a, b Line) (Point, error) {
-	if AreParallel(a, b) {
-		return Point{}, ErrNoIntersection
-	}
-	m, n := det(a.a.X, a.a.Y, a.b.X, a.b.Y), det(b.a.X, b.a.Y, b.b.X, b.b.Y)
-	xn := det(m, a.a.X-a.b.X, n, b.a.X-b.b.X)
-	yn := det(m, a.a.Y-a.b.Y, n, b.a.Y-b.b.Y)
-	d := det(a.a.X-a.b.X, a.a.Y-a.b.Y, b.a.X-b.b.X, b.a.Y-b.b.Y)
-	return Point{X: xn / d, Y: yn / d}, nil
+	return DefaultTolerance().Intersection(a, b)
 }
 
 // RotateAroundOrigin rotates Line l counter-clockwise by Angle rads around
@@ -109,7 +84,78 @@ func Rotate(l Line, p Point, rads Angle) Line {
 		X: det(bx, by, sin, cos) + p.X,
 		Y: det(by, -bx, sin, cos) + p.Y,
 	}
-	return MustLine(NewLineFromPoints(a, b))
+	// Rotating keeps the Points different so the Line doesn't need checking.
+	return Line{a: a, b: b}
+}
+
+// PointOn returns the Point on Line l closest to the origin.
+func PointOn(l Line) Point {
+	return Project(l, Point{X: 0, Y: 0})
+}
+
+// Direction returns the length 1 Vector along Line l pointing from the first
+// Point used to create l to the second.
+func Direction(l Line) Vector {
+	v := Vector{I: -dx(l), J: -dy(l)}
+	d := Length(v)
+	return Vector{I: v.I / d, J: v.J / d}
+}
+
+// Normal returns the length 1 Vector perpendicular to Line l that is Direction
+// rotated counter-clockwise by a quarter turn.
+func Normal(l Line) Vector {
+	d := Direction(l)
+	return Vector{I: -d.J, J: d.I}
+}
+
+// SignedDistance returns the distance from Line l to Point p.
+//
+// The distance is positive if p is on the side of l Normal points to and
+// negative if p is on the other side.
+func SignedDistance(l Line, p Point) Number {
+	return dot(Normal(l), Vector{I: p.X - l.a.X, J: p.Y - l.a.Y})
+}
+
+// Project returns the Point on Line l closest to Point p.
+func Project(l Line, p Point) Point {
+	n, d := Normal(l), SignedDistance(l, p)
+	return Point{X: p.X - d*n.I, Y: p.Y - d*n.J}
+}
+
+// Side of Line l that Point p is on.
+//
+// Returns 1 if p is on the side Normal points to, -1 if p is on the other side,
+// and 0 if p is on l.
+func Side(l Line, p Point) int {
+	d := SignedDistance(l, p)
+	if IsZero(d) {
+		return 0
+	}
+	if d > 0 {
+		return 1
+	}
+	return -1
+}
+
+// Canonical form of Line l which is the same for the same Lines no matter how
+// they were created.
+//
+// The form is a length 1 Vector n perpendicular to l and the offset d of l
+// from the origin along n. The offset is never negative. If l passes through
+// the origin, n points to the side of the y-axis with positive x or up if l is
+// the x-axis. Every Point p on l has the dot-product of n and p equal to d.
+func Canonical(l Line) (Vector, Number) {
+	n := Normal(l)
+	d := dot(n, Vector{I: l.a.X, J: l.a.Y})
+	if IsZero(d) {
+		d = 0
+		if IsZero(n.I) && n.J < 0 || !IsZero(n.I) && n.I < 0 {
+			n = Vector{I: -n.I, J: -n.J}
+		}
+	} else if d < 0 {
+		n, d = Vector{I: -n.I, J: -n.J}, -d
+	}
+	return n, d
 }
 
 // dx returns the x-difference of the Points on the Line.
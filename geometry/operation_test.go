@@ -0,0 +1,141 @@
+package geometry
+
+import "testing"
+
+// TestLineParts checks the parts of Lines don't depend on the Points they were
+// created from except for which way they point.
+func TestLineParts(t *testing.T) {
+	cases := []struct {
+		l         Line
+		on        Point
+		direction Vector
+		normal    Vector
+		offset    Number
+	}{
+		{
+			testLine(-3, 2, 5, 2),
+			Point{X: 0, Y: 2}, Vector{I: 1}, Vector{J: 1}, 2,
+		},
+		{
+			testLine(5, 2, -3, 2),
+			Point{X: 0, Y: 2}, Vector{I: -1}, Vector{J: 1}, 2,
+		},
+		{
+			testLine(-1, 7, -1, 3),
+			Point{X: -1}, Vector{J: -1}, Vector{I: -1}, 1,
+		},
+		{
+			testLine(2, 2, -1, -1),
+			Point{}, Vector{I: -0.7071067811865475, J: -0.7071067811865475},
+			Vector{I: 0.7071067811865475, J: -0.7071067811865475}, 0,
+		},
+		{
+			testLine(0, 2, 2, 0),
+			Point{X: 1, Y: 1}, Vector{I: 0.7071067811865475,
+				J: -0.7071067811865475},
+			Vector{I: 0.7071067811865475, J: 0.7071067811865475},
+			1.4142135623730951,
+		},
+	}
+	for _, c := range cases {
+		if got := PointOn(c.l); !AreSamePoint(got, c.on) {
+			t.Errorf("PointOn(%v) is %v but should be %v", c.l, got, c.on)
+		}
+		if got := Direction(c.l); !sameVector(got, c.direction) {
+			t.Errorf("Direction(%v) is %v but should be %v",
+				c.l, got, c.direction)
+		}
+		n, d := Canonical(c.l)
+		if !sameVector(n, c.normal) || !AreEqual(d, c.offset) {
+			t.Errorf("Canonical(%v) is %v and %v but should be %v and %v",
+				c.l, n, d, c.normal, c.offset)
+		}
+		if got := Normal(c.l); !AreEqual(dot(got, Direction(c.l)), 0) ||
+			!AreEqual(Length(got), 1) {
+			t.Errorf("Normal(%v) is %v which isn't a unit normal", c.l, got)
+		}
+	}
+}
+
+// TestLinePoint checks the distance from, projection onto, and side of Lines
+// of Points.
+func TestLinePoint(t *testing.T) {
+	cases := []struct {
+		l        Line
+		p        Point
+		distance Number
+		project  Point
+		side     int
+	}{
+		{testLine(0, 0, 1, 0), Point{X: 3, Y: 2}, 2, Point{X: 3}, 1},
+		{testLine(1, 0, 0, 0), Point{X: 3, Y: 2}, -2, Point{X: 3}, -1},
+		{testLine(0, 0, 1, 0), Point{X: -4}, 0, Point{X: -4}, 0},
+		{testLine(0, 0, 0, 1), Point{X: 3, Y: 2}, -3, Point{Y: 2}, -1},
+		{
+			testLine(0, 2, 2, 0), Point{}, -1.4142135623730951,
+			Point{X: 1, Y: 1}, -1,
+		},
+		{
+			testLine(1e9, 5, 0, 5), Point{X: 1e9, Y: 5 + 1e-3}, -1e-3,
+			Point{X: 1e9, Y: 5}, -1,
+		},
+	}
+	for _, c := range cases {
+		if got := SignedDistance(c.l, c.p); !AreEqual(got, c.distance) {
+			t.Errorf("SignedDistance(%v, %v) is %v but should be %v",
+				c.l, c.p, got, c.distance)
+		}
+		if got := Project(c.l, c.p); !AreSamePoint(got, c.project) {
+			t.Errorf("Project(%v, %v) is %v but should be %v",
+				c.l, c.p, got, c.project)
+		}
+		if got := Side(c.l, c.p); got != c.side {
+			t.Errorf("Side(%v, %v) is %d but should be %d",
+				c.l, c.p, got, c.side)
+		}
+	}
+}
+
+// TestLineString checks the same Lines look the same no matter how they were
+// created.
+func TestLineString(t *testing.T) {
+	cases := []struct {
+		ls   []Line
+		want string
+	}{
+		{
+			[]Line{
+				testLine(0, 1, 1, 1),
+				testLine(7, 1, -2, 1),
+				MustLine(NewLineFromNormalAndOffset(Vector{J: -2}, -1)),
+			},
+			"{(0 1) (1 1)}",
+		},
+		{
+			[]Line{testLine(3, 0, 3, 1), testLine(3, 9, 3, -9)},
+			"{(3 0) (3 1)}",
+		},
+		{
+			[]Line{
+				testLine(0, 0, 1, 1),
+				testLine(-5, -5, -6, -6),
+				MustLine(NewLineFromPointAndSlope(Point{X: 2, Y: 2}, 1, 1)),
+			},
+			"{(0 0) (0.70710677 0.70710677)}",
+		},
+	}
+	for _, c := range cases {
+		for _, l := range c.ls {
+			if got := l.String(); got != c.want {
+				a, b := l.Points()
+				t.Errorf("the Line through %v and %v looks like %q but "+
+					"should look like %q", a, b, got, c.want)
+			}
+		}
+	}
+}
+
+// sameVector returns true if Vectors a and b are the same within Epsilon.
+func sameVector(a, b Vector) bool {
+	return AreEqual(a.I, b.I) && AreEqual(a.J, b.J)
+}
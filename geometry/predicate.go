@@ -1,37 +1,34 @@
 package geometry
 
-// IsZero returns true if Number n is less than Epsilon difference from 0.
+// IsZero returns true if Number n is at most Epsilon from 0.
 func IsZero(n Number) bool {
-	return AreEqual(n, 0)
+	return DefaultTolerance().IsZero(n)
 }
 
-// AreEqual returns true if a and b are less than Epsilon difference of each
+// AreEqual returns true if Numbers a and b are at most Epsilon from each
 // other.
 func AreEqual(a, b Number) bool {
-	return a-b < Epsilon && b-a < Epsilon
+	return DefaultTolerance().AreEqual(a, b)
 }
 
 // AreSamePoint returns true if Points a and b are the same.
 func AreSamePoint(a, b Point) bool {
-	return AreEqual(a.X, b.X) && AreEqual(a.Y, b.Y)
+	return DefaultTolerance().AreSamePoint(a, b)
 }
 
-// AreParallel returns true if Lines a and b are parallel.
+// AreParallel returns true if Lines a and b are parallel, which is when the
+// sine of the angle between them is at most Epsilon.
 func AreParallel(a, b Line) bool {
-	return IsZero(det(dx(a), dy(a), dx(b), dy(b)))
+	return DefaultTolerance().AreParallel(a, b)
 }
 
-// ArePerpendicular returns true if Lines a and b are perpendicular.
+// ArePerpendicular returns true if Lines a and b are perpendicular, which is
+// when the cosine of the angle between them is at most Epsilon.
 func ArePerpendicular(a, b Line) bool {
-	m, n := Vector{I: dx(a), J: dy(a)}, Vector{I: dx(b), J: dy(b)}
-	return IsZero(dot(m, n))
+	return DefaultTolerance().ArePerpendicular(a, b)
 }
 
 // AreSameLine return true if Lines a and b are the same.
 func AreSameLine(a, b Line) bool {
-	if !AreParallel(a, b) {
-		return false
-	}
-	m, n, c := StandardCoefficients(b)
-	return AreEqual(m*a.a.X+n*a.a.Y, c)
+	return DefaultTolerance().AreSameLine(a, b)
 }
@@ -0,0 +1,308 @@
+package geometry
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ErrNoPolygon is returned when a Polygon without area is given to something
+// expecting a Polygon with area.
+var ErrNoPolygon = errors.New("given information doesn't determine a Polygon")
+
+// Segment is the part of a Line between and including Points A and B.
+type Segment struct{ A, B Point }
+
+// String-representation of the Segment.
+//
+// Looks like '[A B]' where A and B are the Segment's end Points.
+func (s Segment) String() string {
+	return fmt.Sprintf("[%s %s]", s.A, s.B)
+}
+
+// Ray is the part of a Line starting at Point Origin and going forever in the
+// direction of Vector Direction.
+//
+// Direction must not be length 0.
+type Ray struct {
+	Origin    Point
+	Direction Vector
+}
+
+// String-representation of the Ray.
+//
+// Looks like '[Origin Direction)'.
+func (r Ray) String() string {
+	return fmt.Sprintf("[%s %s)", r.Origin, r.Direction)
+}
+
+// Polygon is the closed shape made by connecting each Point to the next and
+// the last Point to the first.
+type Polygon []Point
+
+// String-representation of the Polygon.
+//
+// Looks like '<A B C ...>' where A, B, C, ... are the Polygon's vertices in
+// order. The brackets are different from a Segment's so a Polygon with 2
+// vertices doesn't look like one.
+func (p Polygon) String() string {
+	vs := make([]string, len(p))
+	for i, v := range p {
+		vs[i] = v.String()
+	}
+	return fmt.Sprintf("<%s>", strings.Join(vs, " "))
+}
+
+// Circle is every Point Radius away from Point Center.
+type Circle struct {
+	Center Point
+	Radius Number
+}
+
+// String-representation of the Circle.
+//
+// Looks like '(Center Radius)'.
+func (c Circle) String() string {
+	return fmt.Sprintf("(%s %s)", c.Center, c.Radius)
+}
+
+// Area inside Polygon p.
+//
+// The Area is never negative no matter which way the vertices go around.
+func Area(p Polygon) Number {
+	a, _ := signedArea(p)
+	return abs(a)
+}
+
+// Centroid returns the center of mass of the area inside Polygon p.
+//
+// Returns ErrNoPolygon if p has no area.
+func Centroid(p Polygon) (Point, error) {
+	return DefaultTolerance().Centroid(p)
+}
+
+// IsConvex returns true if Polygon p has area and every line between two
+// Points inside p stays inside p.
+func IsConvex(p Polygon) bool {
+	return DefaultTolerance().IsConvex(p)
+}
+
+// SegmentIntersection returns the Point where Segment s crosses Line l.
+//
+// Returns ErrNoIntersection if s doesn't reach l or lies along l.
+func SegmentIntersection(s Segment, l Line) (Point, error) {
+	return DefaultTolerance().SegmentIntersection(s, l)
+}
+
+// RayIntersection returns the Point where Ray r crosses Line l.
+//
+// Returns ErrNoIntersection if r doesn't reach l or lies along l and
+// ErrNoVector if r's Direction is length 0.
+func RayIntersection(r Ray, l Line) (Point, error) {
+	return DefaultTolerance().RayIntersection(r, l)
+}
+
+// PolygonIntersections returns the Points where the edges of Polygon p meet
+// Line l.
+//
+// The Points are ordered along the Direction of l without duplicates. Both ends
+// of an edge lying along l are included.
+func PolygonIntersections(p Polygon, l Line) []Point {
+	return DefaultTolerance().PolygonIntersections(p, l)
+}
+
+// CircleIntersections returns the Points where Circle c meets Line l.
+//
+// There are no Points if l misses c, 1 if l touches c, and 2 ordered along the
+// Direction of l otherwise.
+func CircleIntersections(c Circle, l Line) []Point {
+	return DefaultTolerance().CircleIntersections(c, l)
+}
+
+// Centroid returns the center of mass of the area inside Polygon p.
+//
+// Returns ErrNoPolygon if p has no area within the Tolerance.
+func (tol Tolerance) Centroid(p Polygon) (Point, error) {
+	a, scale := signedArea(p)
+	if len(p) < 3 || tol.small(a, scale) {
+		return Point{}, ErrNoPolygon
+	}
+	// Vertices are taken relative to the first to keep the products small.
+	o := p[0]
+	var x, y Number
+	for i := range p {
+		m, n := p[i], p[(i+1)%len(p)]
+		mx, my, nx, ny := m.X-o.X, m.Y-o.Y, n.X-o.X, n.Y-o.Y
+		c := det(mx, my, nx, ny)
+		x += (mx + nx) * c
+		y += (my + ny) * c
+	}
+	return Point{X: o.X + x/(6*a), Y: o.Y + y/(6*a)}, nil
+}
+
+// IsConvex returns true if Polygon p has area within the Tolerance and every
+// line between two Points inside p stays inside p.
+//
+// Vertices in the middle of a straight edge are allowed.
+func (tol Tolerance) IsConvex(p Polygon) bool {
+	if _, err := tol.Centroid(p); err != nil {
+		return false
+	}
+	sign, turned := 0, 0.0
+	for i := range p {
+		m, n, o := p[i], p[(i+1)%len(p)], p[(i+2)%len(p)]
+		u := Vector{I: n.X - m.X, J: n.Y - m.Y}
+		v := Vector{I: o.X - n.X, J: o.Y - n.Y}
+		if tol.IsZero(Length(u)) || tol.IsZero(Length(v)) {
+			continue
+		}
+		c := det(u.I, u.J, v.I, v.J) / (Length(u) * Length(v))
+		d := dot(u, v) / (Length(u) * Length(v))
+		if tol.small(c, 1) {
+			if d < 0 {
+				return false
+			}
+			continue
+		}
+		s := 1
+		if c < 0 {
+			s = -1
+		}
+		if sign != 0 && s != sign {
+			return false
+		}
+		sign = s
+		turned += math.Atan2(float64(c), float64(d))
+	}
+	// Turning the same way every time still allows edges crossing each other
+	// so the turns must add up to exactly one full turn.
+	return math.Abs(math.Abs(turned)-2*math.Pi) < math.Pi
+}
+
+// SegmentIntersection returns the Point where Segment s crosses Line l.
+//
+// Returns ErrNoIntersection if s doesn't reach l or lies along l within the
+// Tolerance.
+func (tol Tolerance) SegmentIntersection(s Segment, l Line) (Point, error) {
+	da, db := SignedDistance(l, s.A), SignedDistance(l, s.B)
+	onA, onB := tol.onLine(l, s.A), tol.onLine(l, s.B)
+	switch {
+	case onA && onB && !tol.AreSamePoint(s.A, s.B):
+		return Point{}, ErrNoIntersection
+	case onA:
+		return s.A, nil
+	case onB:
+		return s.B, nil
+	case da > 0 && db > 0 || da < 0 && db < 0:
+		return Point{}, ErrNoIntersection
+	}
+	u := da / (da - db)
+	return Point{X: s.A.X + u*(s.B.X-s.A.X), Y: s.A.Y + u*(s.B.Y-s.A.Y)}, nil
+}
+
+// RayIntersection returns the Point where Ray r crosses Line l.
+//
+// Returns ErrNoIntersection if r doesn't reach l or lies along l within the
+// Tolerance and ErrNoVector if r's Direction is length 0 within the Tolerance.
+func (tol Tolerance) RayIntersection(r Ray, l Line) (Point, error) {
+	if tol.IsZero(Length(r.Direction)) {
+		return Point{}, ErrNoVector
+	}
+	along := Line{
+		a: r.Origin,
+		b: Point{X: r.Origin.X + r.Direction.I, Y: r.Origin.Y + r.Direction.J},
+	}
+	if tol.AreParallel(along, l) {
+		return Point{}, ErrNoIntersection
+	}
+	if tol.onLine(l, r.Origin) {
+		return r.Origin, nil
+	}
+	u := -SignedDistance(l, r.Origin) / dot(Normal(l), r.Direction)
+	if u < 0 {
+		return Point{}, ErrNoIntersection
+	}
+	return Point{
+		X: r.Origin.X + u*r.Direction.I,
+		Y: r.Origin.Y + u*r.Direction.J,
+	}, nil
+}
+
+// PolygonIntersections returns the Points where the edges of Polygon p meet
+// Line l.
+//
+// The Points are ordered along the Direction of l without duplicates within the
+// Tolerance. Both ends of an edge lying along l are included.
+func (tol Tolerance) PolygonIntersections(p Polygon, l Line) []Point {
+	var ps []Point
+	for i := range p {
+		s := Segment{A: p[i], B: p[(i+1)%len(p)]}
+		if q, err := tol.SegmentIntersection(s, l); err == nil {
+			ps = append(ps, q)
+		} else if tol.onLine(l, s.A) {
+			ps = append(ps, s.A, s.B)
+		}
+	}
+	d := Direction(l)
+	sort.Slice(ps, func(i, j int) bool {
+		return dot(d, Vector{I: ps[i].X, J: ps[i].Y}) <
+			dot(d, Vector{I: ps[j].X, J: ps[j].Y})
+	})
+	var out []Point
+	for _, q := range ps {
+		if len(out) == 0 || !tol.AreSamePoint(out[len(out)-1], q) {
+			out = append(out, q)
+		}
+	}
+	return out
+}
+
+// CircleIntersections returns the Points where Circle c meets Line l.
+//
+// There are no Points if l misses c, 1 if l touches c within the Tolerance,
+// and 2 ordered along the Direction of l otherwise.
+func (tol Tolerance) CircleIntersections(c Circle, l Line) []Point {
+	d, r := abs(SignedDistance(l, c.Center)), abs(c.Radius)
+	f := Project(l, c.Center)
+	if tol.AreEqual(d, r) {
+		return []Point{f}
+	}
+	if d > r {
+		return nil
+	}
+	h, v := Number(math.Sqrt(float64(r*r-d*d))), Direction(l)
+	return []Point{
+		{X: f.X - h*v.I, Y: f.Y - h*v.J},
+		{X: f.X + h*v.I, Y: f.Y + h*v.J},
+	}
+}
+
+// onLine returns true if Point p is on Line l within the Tolerance compared to
+// the offset of l from the origin like AreSameLine.
+//
+// How far p is from the origin isn't used so that p being far along l doesn't
+// let it be far off l too.
+func (tol Tolerance) onLine(l Line, p Point) bool {
+	_, d := Canonical(l)
+	return tol.small(SignedDistance(l, p), d)
+}
+
+// signedArea returns the area inside Polygon p, which is positive if the
+// vertices go counter-clockwise and negative otherwise, along with the area
+// the Tolerance is compared against.
+func signedArea(p Polygon) (Number, Number) {
+	if len(p) == 0 {
+		return 0, 0
+	}
+	o := p[0]
+	var a, scale Number
+	for i := range p {
+		m, n := p[i], p[(i+1)%len(p)]
+		c := det(m.X-o.X, m.Y-o.Y, n.X-o.X, n.Y-o.Y)
+		a += c
+		scale += abs(c)
+	}
+	return a / 2, scale / 2
+}
@@ -0,0 +1,63 @@
+package geometry
+
+import "testing"
+
+// testSegment returns the Segment from (ax ay) to (bx by).
+func testSegment(ax, ay, bx, by Number) Segment {
+	return Segment{A: Point{X: ax, Y: ay}, B: Point{X: bx, Y: by}}
+}
+
+// TestOnLineFar checks Points far from the origin are only on a Line when
+// they're close to it.
+func TestOnLineFar(t *testing.T) {
+	l := testLine(0, 0, 1, 0)
+	cases := []struct {
+		s    Segment
+		want error
+	}{
+		{testSegment(1e9, 1, 1e9, 500), ErrNoIntersection},
+		{testSegment(1e9, 1e-3, 1e9+1, 1e-3), ErrNoIntersection},
+		{testSegment(1e9, 0, 1e9, 2), nil},
+		{testSegment(1e9, -1, 1e9, 1), nil},
+	}
+	tols := []Tolerance{DefaultTolerance(), {Absolute: Epsilon, Relative: Epsilon}}
+	for _, tol := range tols {
+		for _, c := range cases {
+			p, err := tol.SegmentIntersection(c.s, l)
+			if err != c.want {
+				t.Errorf("%v meets %v at %v with %v within %v but should "+
+					"give %v", c.s, l, p, err, tol, c.want)
+			}
+		}
+		sq := Polygon{
+			{X: 1e9, Y: 1},
+			{X: 1e9 + 1, Y: 1},
+			{X: 1e9 + 1, Y: 2},
+			{X: 1e9, Y: 2},
+		}
+		if ps := tol.PolygonIntersections(sq, l); len(ps) != 0 {
+			t.Errorf("%v meets %v at %v within %v", sq, l, ps, tol)
+		}
+	}
+}
+
+// TestShapeString checks the String-representations of shapes.
+func TestShapeString(t *testing.T) {
+	a, b := Point{X: 1, Y: 2}, Point{X: 3, Y: 4}
+	cases := []struct {
+		s    interface{ String() string }
+		want string
+	}{
+		{Segment{A: a, B: b}, "[(1 2) (3 4)]"},
+		{Ray{Origin: a, Direction: Vector{I: 1, J: 0}}, "[(1 2) <1 0>)"},
+		{Polygon{a, b}, "<(1 2) (3 4)>"},
+		{Polygon{a, b, {X: 0, Y: 0}}, "<(1 2) (3 4) (0 0)>"},
+		{Circle{Center: a, Radius: 5}, "((1 2) 5)"},
+	}
+	for _, c := range cases {
+		if got := c.s.String(); got != c.want {
+			t.Errorf("%#v looks like %s but should look like %s",
+				c.s, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package geometry
+
+import (
+	"math"
+)
+
+// DefaultTolerance returns the Tolerance used by everything that doesn't take
+// a Tolerance.
+//
+// Numbers must have a difference of at most Epsilon to be equal. There is no
+// relative part so Numbers are compared the same no matter their sizes.
+//
+// Is a function so the Tolerance can't be changed for everything at once.
+func DefaultTolerance() Tolerance {
+	return Tolerance{Absolute: Epsilon}
+}
+
+// Tolerance is how close Numbers must be for them to be considered equal.
+//
+// Numbers are equal if their difference is at most Absolute or at most
+// Relative times the larger of their sizes. Absolute suits data where every
+// Number is around the same size and Relative, which is 0 unless it's set,
+// suits data where the sizes vary. Predicates on Points use the Tolerance on
+// their coordinates. Lines are parallel when the sine of the angle between them
+// is within the Tolerance of 0 and perpendicular when the cosine is, where the
+// size Relative is compared against is 1 since angles don't depend on the size
+// of anything. Lines are the same when they're parallel and the distance from
+// each to a Point on the other is within the Tolerance of 0 compared to the
+// Line's offset from the origin.
+type Tolerance struct {
+	Absolute, Relative Number
+}
+
+// small returns true if the size of Number n is at most the Tolerance compared
+// to the size scale.
+//
+// The comparison includes the Tolerance itself so a Tolerance of 0 still finds
+// 0 small.
+func (tol Tolerance) small(n, scale Number) bool {
+	n, scale = abs(n), abs(scale)
+	return n <= tol.Absolute || n <= tol.Relative*scale
+}
+
+// IsZero returns true if Number n is within the Tolerance of 0.
+func (tol Tolerance) IsZero(n Number) bool {
+	return tol.AreEqual(n, 0)
+}
+
+// AreEqual returns true if Numbers a and b are within the Tolerance of each
+// other.
+func (tol Tolerance) AreEqual(a, b Number) bool {
+	return tol.small(a-b, Number(math.Max(float64(abs(a)), float64(abs(b)))))
+}
+
+// AreSamePoint returns true if Points a and b are the same within the
+// Tolerance.
+func (tol Tolerance) AreSamePoint(a, b Point) bool {
+	return tol.AreEqual(a.X, b.X) && tol.AreEqual(a.Y, b.Y)
+}
+
+// AreParallel returns true if Lines a and b are parallel within the Tolerance.
+func (tol Tolerance) AreParallel(a, b Line) bool {
+	m, n := Vector{I: dx(a), J: dy(a)}, Vector{I: dx(b), J: dy(b)}
+	return tol.small(det(m.I, m.J, n.I, n.J)/(Length(m)*Length(n)), 1)
+}
+
+// ArePerpendicular returns true if Lines a and b are perpendicular within the
+// Tolerance.
+func (tol Tolerance) ArePerpendicular(a, b Line) bool {
+	m, n := Vector{I: dx(a), J: dy(a)}, Vector{I: dx(b), J: dy(b)}
+	return tol.small(dot(m, n)/(Length(m)*Length(n)), 1)
+}
+
+// AreSameLine returns true if Lines a and b are the same within the Tolerance.
+//
+// The distance from each Line to the Point on the other closest to the origin
+// is compared to the first Line's offset from the origin so the order of the
+// Lines doesn't matter and Points far along a Line don't let it be far off.
+func (tol Tolerance) AreSameLine(a, b Line) bool {
+	return tol.AreParallel(a, b) &&
+		tol.onLine(a, PointOn(b)) && tol.onLine(b, PointOn(a))
+}
+
+// NewLineFromPoints creates a Line from 2 Points on the Line.
+//
+// Returns ErrNoLine if both Points are the same within the Tolerance.
+func (tol Tolerance) NewLineFromPoints(a, b Point) (Line, error) {
+	if tol.AreSamePoint(a, b) {
+		return Line{}, ErrNoLine
+	}
+	return Line{a: a, b: b}, nil
+}
+
+// Scale a Vector to a new one of the same direction but given length.
+//
+// Returns ErrNoVector if v is length 0 within the Tolerance since the scaled
+// Vector's direction can't be determined.
+func (tol Tolerance) Scale(v Vector, l Number) (Vector, error) {
+	cl := Length(v)
+	if tol.IsZero(cl) {
+		return Vector{}, ErrNoVector
+	}
+	return Vector{I: l * v.I / cl, J: l * v.J / cl}, nil
+}
+
+// Intersection returns the Point where Lines a and b intersect.
+//
+// Returns ErrNoIntersection if the Lines are parallel within the Tolerance.
+func (tol Tolerance) Intersection(a, b Line) (Point, error) {
+	if tol.AreParallel(a, b) {
+		return Point{}, ErrNoIntersection
+	}
+	m, n := det(a.a.X, a.a.Y, a.b.X, a.b.Y), det(b.a.X, b.a.Y, b.b.X, b.b.Y)
+	xn := det(m, a.a.X-a.b.X, n, b.a.X-b.b.X)
+	yn := det(m, a.a.Y-a.b.Y, n, b.a.Y-b.b.Y)
+	d := det(a.a.X-a.b.X, a.a.Y-a.b.Y, b.a.X-b.b.X, b.a.Y-b.b.Y)
+	return Point{X: xn / d, Y: yn / d}, nil
+}
+
+// ShortestVector returns the shortest Vector from Lines a to b.
+//
+// The Vector has length 0 if the Lines intersect as described by the
+// Tolerance.
+func (tol Tolerance) ShortestVector(a, b Line) Vector {
+	if !tol.AreParallel(a, b) {
+		return Vector{I: 0, J: 0}
+	}
+	p := Perpendicular(a)
+	m, n := MustPoint(tol.Intersection(a, p)), MustPoint(tol.Intersection(b, p))
+	return Vector{I: n.X - m.X, J: n.Y - m.Y}
+}
+
+// AngleBetween Lines a and b so that it is the shortest Angle to rotate a
+// counter-clockwise to be parallel to b.
+//
+// The Angle is 0 if the Lines are parallel within the Tolerance.
+func (tol Tolerance) AngleBetween(a, b Line) Angle {
+	if tol.AreParallel(a, b) {
+		return 0
+	}
+	radsa := math.Atan2(float64(dy(a)), float64(dx(a)))
+	radsb := math.Atan2(float64(dy(b)), float64(dx(b)))
+	rads := math.Mod(radsa-radsb, 2*math.Pi)
+	i := MustPoint(tol.Intersection(a, b))
+	if !tol.AreParallel(Rotate(a, i, Angle(rads)), b) {
+		rads = 2*math.Pi - rads
+	}
+	return Angle(rads)
+}
+
+// abs returns the size of Number n.
+func abs(n Number) Number {
+	return Number(math.Abs(float64(n)))
+}
@@ -0,0 +1,117 @@
+package geometry
+
+import "testing"
+
+// testLine returns the Line through (ax ay) and (bx by).
+func testLine(ax, ay, bx, by Number) Line {
+	return MustLine(NewLineFromPoints(Point{X: ax, Y: ay}, Point{X: bx, Y: by}))
+}
+
+// TestDefaultToleranceAbsolute checks DefaultTolerance compares Numbers the
+// same no matter their sizes.
+func TestDefaultToleranceAbsolute(t *testing.T) {
+	cases := []struct {
+		a, b Number
+		want bool
+	}{
+		{0, 0, true},
+		{1, 1 + 1e-8, true},
+		{1, 1 + 1e-6, false},
+		{1e8, 1e8 + 5, false},
+		{1e8, 1e8, true},
+	}
+	for _, c := range cases {
+		if got := AreEqual(c.a, c.b); got != c.want {
+			t.Errorf("AreEqual(%v, %v) is %v but should be %v",
+				float64(c.a), float64(c.b), got, c.want)
+		}
+	}
+	a, b := Point{X: 1e9, Y: 0}, Point{X: 1e9 + 1, Y: 0}
+	if _, err := NewLineFromPoints(a, b); err != nil {
+		t.Errorf("NewLineFromPoints(%v, %v) gives %v", a, b, err)
+	}
+}
+
+// TestToleranceZero checks a Tolerance of 0 only finds exactly equal Numbers
+// equal.
+func TestToleranceZero(t *testing.T) {
+	var tol Tolerance
+	if !tol.IsZero(0) {
+		t.Errorf("0 isn't zero within %v", tol)
+	}
+	if tol.IsZero(1e-300) {
+		t.Errorf("1e-300 is zero within %v", tol)
+	}
+	p := Point{X: 1, Y: 2}
+	if _, err := tol.NewLineFromPoints(p, p); err != ErrNoLine {
+		t.Errorf("NewLineFromPoints(%v, %v) gives %v but should give %v",
+			p, p, err, ErrNoLine)
+	}
+	a := testLine(0, 0, 1, 0)
+	if !tol.AreParallel(a, a) || !tol.AreSameLine(a, a) {
+		t.Errorf("%v isn't parallel to and the same as itself", a)
+	}
+}
+
+// TestToleranceRelative checks the relative part of a Tolerance is only used
+// when it's set.
+func TestToleranceRelative(t *testing.T) {
+	tol := Tolerance{Absolute: Epsilon, Relative: 1e-6}
+	if !tol.AreEqual(1e8, 1e8+5) {
+		t.Errorf("1e8 and 1e8+5 aren't equal within %v", tol)
+	}
+	if tol.AreEqual(1, 1.001) {
+		t.Errorf("1 and 1.001 are equal within %v", tol)
+	}
+}
+
+// TestAreSameLine checks Lines are the same no matter the order they're
+// compared in or the Points they were created from.
+func TestAreSameLine(t *testing.T) {
+	tols := []Tolerance{
+		DefaultTolerance(),
+		{Absolute: Epsilon, Relative: Epsilon},
+	}
+	cases := []struct {
+		a, b Line
+		want bool
+	}{
+		{testLine(1e9, 0, 0, 0), testLine(0, 1, 1, 1), false},
+		{testLine(1e9, 0, 0, 0), testLine(-3, 0, 5, 0), true},
+		{testLine(1e9, 1e9, 0, 0), testLine(1, 1, 2, 2), true},
+		{testLine(0, 5, 1, 5), testLine(0, 5+1e-3, 1, 5+1e-3), false},
+		{testLine(0, 0, 1, 1), testLine(0, 0, 1, -1), false},
+	}
+	for _, tol := range tols {
+		for _, c := range cases {
+			for _, ls := range [][2]Line{{c.a, c.b}, {c.b, c.a}} {
+				got := tol.AreSameLine(ls[0], ls[1])
+				if got != c.want {
+					t.Errorf("AreSameLine(%v, %v) within %v is %v but "+
+						"should be %v", ls[0], ls[1], tol, got, c.want)
+				}
+			}
+		}
+	}
+}
+
+// TestAreParallelAbsolute checks both parts of a Tolerance are used for
+// parallel and perpendicular Lines.
+func TestAreParallelAbsolute(t *testing.T) {
+	a, b := testLine(0, 0, 1, 0), testLine(0, 0, 1, 0.001)
+	c := testLine(0, 0, 0.001, 1)
+	for _, tol := range []Tolerance{
+		{Absolute: 0.01},
+		{Relative: 0.01},
+	} {
+		if !tol.AreParallel(a, b) {
+			t.Errorf("%v and %v aren't parallel within %v", a, b, tol)
+		}
+		if !tol.ArePerpendicular(a, c) {
+			t.Errorf("%v and %v aren't perpendicular within %v", a, c, tol)
+		}
+	}
+	if DefaultTolerance().AreParallel(a, b) {
+		t.Errorf("%v and %v are parallel within %v", a, b, DefaultTolerance())
+	}
+}
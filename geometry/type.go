@@ -1,5 +1,5 @@
 // Package geometry defines geometric primitives like Number, Point, Vector, and
-// Line.
+// Line along with shapes like Segment, Ray, Polygon, and Circle.
 //
 // The package also defines operations and predicates on those primitives.
 package geometry
@@ -11,8 +11,8 @@ import (
 	"strconv"
 )
 
-// Epsilon is the Number that two Numbers must have a difference with each other
-// less than for them to be considered equal.
+// Epsilon is the largest difference two Numbers can have with each other and
+// still be considered equal.
 const Epsilon = Number(0.0000001)
 
 var (
@@ -86,17 +86,47 @@ func NewLineFromPointAndSlope(p Point, rise, run Number) (Line, error) {
 //
 // Returns ErrNoLine if both Points are the same.
 func NewLineFromPoints(a, b Point) (Line, error) {
-	if AreSamePoint(a, b) {
+	return DefaultTolerance().NewLineFromPoints(a, b)
+}
+
+// NewLineFromNormalAndOffset creates a Line from a Vector n perpendicular to
+// the Line and the offset d of the Line along n from the origin.
+//
+// The Line is every Point p where the dot-product of n and p is d times the
+// length of n.
+//
+// Returns ErrNoLine if n is length 0.
+func NewLineFromNormalAndOffset(n Vector, d Number) (Line, error) {
+	u, err := Scale(n, 1)
+	if err != nil {
 		return Line{}, ErrNoLine
 	}
-	return Line{a: a, b: b}, nil
+	p := Point{X: d * u.I, Y: d * u.J}
+	return NewLineFromPoints(p, Point{X: p.X - u.J, Y: p.Y + u.I})
 }
 
 // String-representation of the Line.
 //
-// Looks like '{A B}' where A and B are two different Points on the Line.
+// Looks like '{A B}' where A and B are two different Points on the Line. A is
+// the Point on the Line closest to the origin and B is 1 away from A to the
+// right or straight up if the Line is vertical so that the same Lines look the
+// same no matter how they were created.
 func (l Line) String() string {
-	return fmt.Sprintf("{%s %s}", l.a, l.b)
+	n, d := Canonical(l)
+	a := Point{X: d * n.I, Y: d * n.J}
+	v := Vector{I: -n.J, J: n.I}
+	if IsZero(v.I) && v.J < 0 || !IsZero(v.I) && v.I < 0 {
+		v = Vector{I: -v.I, J: -v.J}
+	}
+	return fmt.Sprintf("{%s %s}", a, Point{X: a.X + v.I, Y: a.Y + v.J})
+}
+
+// Points returns the 2 different Points the Line was created from.
+//
+// Unlike the Points in the String-representation, these are exactly the Points
+// that were passed so the same Line can be created again without rounding.
+func (l Line) Points() (Point, Point) {
+	return l.a, l.b
 }
 
 // Vector is a representation of a direction and a magnitude where the direction
@@ -132,12 +162,38 @@ type Angle Number
 //
 // Looks like the equivalent Angle with the smallest distance to 0.
 func (a Angle) String() string {
-	rads := math.Mod(float64(a), 2*math.Pi)
-	if rads > math.Pi {
-		rads = -rads - 2*math.Pi
+	return a.In(Radians)
+}
+
+// In returns the String-representation of the Angle in AngleUnit u.
+//
+// Looks like String for Radians, like '90deg' for Degrees, and like '0.5pi' for
+// PiRadians so the parse package can read it back. The Angle is the same
+// equivalent Angle as String's in every AngleUnit which is the one in
+// (-pi, pi].
+func (a Angle) In(u AngleUnit) string {
+	rads := math.Remainder(float64(a), 2*math.Pi)
+	if rads == -math.Pi {
+		rads = math.Pi
 	}
-	if rads < -math.Pi {
-		rads = -2*math.Pi - rads
+	switch u {
+	case Degrees:
+		return Number(rads*180/math.Pi).String() + "deg"
+	case PiRadians:
+		return Number(rads/math.Pi).String() + "pi"
 	}
 	return Number(rads).String()
 }
+
+// AngleUnit is a unit Angles can be written in.
+type AngleUnit int
+
+// AngleUnits.
+const (
+	// Radians is the AngleUnit Angles are in.
+	Radians AngleUnit = iota
+	// Degrees are 180 per pi Radians.
+	Degrees
+	// PiRadians are multiples of pi Radians.
+	PiRadians
+)
@@ -0,0 +1,235 @@
+package parse
+
+import "unicode/utf8"
+
+// File is the syntax of a transform.Transformation's string.
+type File struct {
+	// Exprs are the top-level expressions, one per line, which are composed
+	// in order except for LetExprs and IncludeExprs which can only be
+	// top-level.
+	Exprs []Expr
+	// Comments in the order they appear.
+	Comments []Comment
+	// src is the string the File was parsed from.
+	src string
+}
+
+// Comment is a '#' and the rest of the line after it.
+type Comment struct {
+	Pos  Position
+	Text string
+}
+
+// Expr is an expression in a File.
+//
+// Expr is one of *CallExpr, *LineExpr, *PointExpr, *VectorExpr, *NumberExpr,
+// *IdentExpr, *UnaryExpr, *BinaryExpr, *ParenExpr, *LetExpr, or
+// *IncludeExpr.
+type Expr interface {
+	// Pos is the Position of the first character of the Expr.
+	Pos() Position
+	// End is the Position of the character after the Expr.
+	End() Position
+}
+
+// CallExpr is a called transform.Transformation-constructor or operator like
+// 'Rotation((0 0), 1)' or 'Compose(a, b)'.
+type CallExpr struct {
+	Name    string
+	NamePos Position
+	Args    []Expr
+	Rparen  Position
+}
+
+// Pos of the CallExpr's name.
+func (x *CallExpr) Pos() Position {
+	return x.NamePos
+}
+
+// End after the CallExpr's ')'.
+func (x *CallExpr) End() Position {
+	return after(x.Rparen, 1)
+}
+
+// LineExpr is a geometry.Line like '{(0 0) (1 1)}'.
+type LineExpr struct {
+	Lbrace Position
+	A, B   Expr
+	Rbrace Position
+}
+
+// Pos of the LineExpr's '{'.
+func (x *LineExpr) Pos() Position {
+	return x.Lbrace
+}
+
+// End after the LineExpr's '}'.
+func (x *LineExpr) End() Position {
+	return after(x.Rbrace, 1)
+}
+
+// PointExpr is a geometry.Point like '(0 1)'.
+type PointExpr struct {
+	Lparen Position
+	X, Y   Expr
+	Rparen Position
+}
+
+// Pos of the PointExpr's '('.
+func (x *PointExpr) Pos() Position {
+	return x.Lparen
+}
+
+// End after the PointExpr's ')'.
+func (x *PointExpr) End() Position {
+	return after(x.Rparen, 1)
+}
+
+// VectorExpr is a geometry.Vector like '<0 1>'.
+type VectorExpr struct {
+	Langle Position
+	I, J   Expr
+	Rangle Position
+}
+
+// Pos of the VectorExpr's '<'.
+func (x *VectorExpr) Pos() Position {
+	return x.Langle
+}
+
+// End after the VectorExpr's '>'.
+func (x *VectorExpr) End() Position {
+	return after(x.Rangle, 1)
+}
+
+// NumberExpr is an unsigned number like '1.5' kept as written and followed by
+// the name of a constant it's multiplied by like the 'deg' in '90deg' if there
+// is one.
+type NumberExpr struct {
+	ValuePos Position
+	Value    string
+	Unit     string
+}
+
+// Pos of the NumberExpr's first character.
+func (x *NumberExpr) Pos() Position {
+	return x.ValuePos
+}
+
+// End after the NumberExpr's last character.
+func (x *NumberExpr) End() Position {
+	return after(x.ValuePos, len(x.Value)+utf8.RuneCountInString(x.Unit))
+}
+
+// IdentExpr is the name of a constant like 'pi'.
+type IdentExpr struct {
+	NamePos Position
+	Name    string
+}
+
+// Pos of the IdentExpr's name.
+func (x *IdentExpr) Pos() Position {
+	return x.NamePos
+}
+
+// End after the IdentExpr's name.
+func (x *IdentExpr) End() Position {
+	return after(x.NamePos, utf8.RuneCountInString(x.Name))
+}
+
+// UnaryExpr is a signed expression like '-pi'.
+type UnaryExpr struct {
+	OpPos Position
+	// Op is '+' or '-'.
+	Op string
+	X  Expr
+}
+
+// Pos of the UnaryExpr's sign.
+func (x *UnaryExpr) Pos() Position {
+	return x.OpPos
+}
+
+// End after the UnaryExpr's expression.
+func (x *UnaryExpr) End() Position {
+	return x.X.End()
+}
+
+// BinaryExpr is arithmetic on two expressions like 'pi/3'.
+type BinaryExpr struct {
+	X     Expr
+	OpPos Position
+	// Op is '+', '-', '*', or '/'.
+	Op string
+	Y  Expr
+}
+
+// Pos of the BinaryExpr's first expression.
+func (x *BinaryExpr) Pos() Position {
+	return x.X.Pos()
+}
+
+// End after the BinaryExpr's second expression.
+func (x *BinaryExpr) End() Position {
+	return x.Y.End()
+}
+
+// ParenExpr is an expression in parentheses like '(1+2)'.
+type ParenExpr struct {
+	Lparen Position
+	X      Expr
+	Rparen Position
+}
+
+// Pos of the ParenExpr's '('.
+func (x *ParenExpr) Pos() Position {
+	return x.Lparen
+}
+
+// End after the ParenExpr's ')'.
+func (x *ParenExpr) End() Position {
+	return after(x.Rparen, 1)
+}
+
+// LetExpr defines a name for an expression like 'let m = LineReflection(l)'.
+type LetExpr struct {
+	Let     Position
+	Name    string
+	NamePos Position
+	Value   Expr
+}
+
+// Pos of the LetExpr's 'let'.
+func (x *LetExpr) Pos() Position {
+	return x.Let
+}
+
+// End after the LetExpr's expression.
+func (x *LetExpr) End() Position {
+	return x.Value.End()
+}
+
+// IncludeExpr includes another file like 'include "other.txt"'.
+type IncludeExpr struct {
+	Include Position
+	PathPos Position
+	// Path to the file without quotes or escapes.
+	Path string
+	// Value is the quoted Path as written.
+	Value string
+}
+
+// Pos of the IncludeExpr's 'include'.
+func (x *IncludeExpr) Pos() Position {
+	return x.Include
+}
+
+// End after the IncludeExpr's quoted path.
+func (x *IncludeExpr) End() Position {
+	return after(x.PathPos, utf8.RuneCountInString(x.Value))
+}
+
+// after returns the Position n characters after Position p on the same line.
+func after(p Position, n int) Position {
+	return Position{Line: p.Line, Column: p.Column + n}
+}
@@ -0,0 +1,134 @@
+package parse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error is an error that happened at a place in the string being parsed.
+//
+// Err is one of the package's ErrBad errors, ErrInexactAngle, ErrIncludeCycle,
+// an error from the geometry package, transform.ErrNotIsometry, or an error
+// from opening an included file so errors.Is can check what went wrong.
+type Error struct {
+	// Line and Column where the problem starts as described by Position.
+	Line, Column int
+	// Snippet is the whole line of the string the problem is on.
+	Snippet string
+	// Hint is a suggestion for fixing the problem.
+	Hint string
+	// Err is what went wrong.
+	Err error
+	// Path of the included file the problem is in or empty if it's in the
+	// string being parsed.
+	Path string
+	// specific is true if Hint is about a specific problem and should be
+	// kept when the Error is reported as a problem with a larger part of the
+	// string.
+	specific bool
+}
+
+// Error looks like 'Line:Column: Err'.
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d:%d: %v", e.Line, e.Column, e.Err)
+}
+
+// Unwrap returns Err.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Hints for the errors of each part of a string.
+const (
+	hintTransformation = "transformations look like 'Name(argument, ...)'"
+	hintLine           = "lines look like '{(ax ay) (bx by)}'"
+	hintPoint          = "points look like '(x y)'"
+	hintVector         = "vectors look like '<i j>'"
+	hintPointPair      = "point-pairs look like '(x y) (x y)'"
+	hintNumber         = "numbers look like '1', '-2.5', '1e-3', or 'pi/3'"
+	hintAngle          = "angles are radians like 'pi/2' or degrees like '90deg'"
+	hintConstant       = "the constants are 'pi', 'tau', and 'deg'"
+	hintDivide         = "numbers can't be divided by 0"
+	hintFinite         = "numbers must be between about -1.8e308 and 1.8e308"
+	hintInteger        = "the number of times must be a whole number like '2'"
+	hintLet            = "definitions look like 'let name = expression'"
+	hintInclude        = "includes look like 'include \"other.txt\"'"
+	hintNoLine         = "the two points of a line must be different"
+	hintExact          = "exact numbers look like '1', '-2.5', or '1/3'"
+	hintExactConstant  = "exact numbers can't use 'pi', 'tau', or 'deg'"
+	hintInexact        = "exact rotations must be by a multiple of " +
+		"a quarter turn like 'pi/2' or '90deg'"
+	hintJSON = "JSON looks like a transformation object or an array " +
+		"of them"
+	hintJSONType = `"type" is one of "NoTransformation", ` +
+		`"LineReflection", "Translation", "Rotation", or "GlideReflection"`
+	hintJSONLine  = `lines look like '{"a": point, "b": point}'`
+	hintJSONPoint = `points look like '{"x": 1, "y": 2}'`
+)
+
+// mismatch returns an *Error at Expr x caused by err with hint for x not being
+// what was expected or with the hint from undefined if x is a name that isn't
+// defined.
+func mismatch(x Expr, err error, hint string) *Error {
+	if id, ok := x.(*IdentExpr); ok {
+		if _, ok := constants[id.Name]; !ok {
+			return problemAt(x.Pos(), err, undefined(id.Name))
+		}
+	}
+	return errorAt(x.Pos(), err, hint)
+}
+
+// undefined returns the hint for the name not being defined.
+func undefined(name string) string {
+	return fmt.Sprintf(
+		"'%s' isn't defined with 'let' or one of 'pi', 'tau', and 'deg'",
+		name,
+	)
+}
+
+// start is the Position of the first character of a string.
+var start = Position{Line: 1, Column: 1}
+
+// errorAt returns an *Error at Position p caused by err with hint.
+func errorAt(p Position, err error, hint string) *Error {
+	return &Error{Line: p.Line, Column: p.Column, Err: err, Hint: hint}
+}
+
+// problemAt returns an *Error like errorAt whose hint is about a specific
+// problem and is kept by recause.
+func problemAt(p Position, err error, hint string) *Error {
+	e := errorAt(p, err, hint)
+	e.specific = true
+	return e
+}
+
+// recause returns err with its cause replaced by cause and its hint replaced
+// by hint if err is an *Error and an *Error at Position p otherwise.
+//
+// This keeps where a problem was found while reporting it as a problem with
+// the larger part of the string it's in. Hints from problemAt are kept since
+// they say more than hint.
+func recause(err error, p Position, cause error, hint string) *Error {
+	e, ok := err.(*Error)
+	if !ok {
+		return errorAt(p, cause, hint)
+	}
+	p = Position{Line: e.Line, Column: e.Column}
+	if e.specific {
+		return problemAt(p, cause, e.Hint)
+	}
+	return errorAt(p, cause, hint)
+}
+
+// withSnippet fills in the Snippet of err if it's an *Error from the string x.
+func withSnippet(err error, x string) error {
+	e, ok := err.(*Error)
+	if !ok || e.Snippet != "" {
+		return err
+	}
+	lines := strings.Split(x, "\n")
+	if e.Line >= 1 && e.Line <= len(lines) {
+		e.Snippet = strings.TrimRight(lines[e.Line-1], "\r")
+	}
+	return e
+}
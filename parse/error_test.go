@@ -0,0 +1,150 @@
+package parse
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jwowillo/viztransform/geometry"
+)
+
+// TestErrorPositions checks bad strings give *Errors at the character the
+// problem starts at with the line it's on and a hint saying what's wrong.
+func TestErrorPositions(t *testing.T) {
+	cases := []struct {
+		x            string
+		cause        error
+		line, column int
+		hint         string
+	}{
+		{
+			"Spin((0 0), 1)", ErrBadTransformation, 1, 1,
+			"'Spin' isn't NoTransformation, LineReflection, Translation, " +
+				"Rotation, GlideReflection, Compose, Inverse, Power, or " +
+				"Conjugate",
+		},
+		{
+			"Rotation((0 0), 1", ErrBadTransformation, 1, 18,
+			"arguments are separated by ',' and end with ')'",
+		},
+		{
+			"Rotation((0 0))", ErrBadTransformation, 1, 1,
+			"'Rotation' takes 2 arguments but got 1",
+		},
+		{
+			"Rotation((0 0), foo)", ErrBadAngle, 1, 17, undefined("foo"),
+		},
+		{"Rotation((0 0), 1/0)", ErrBadAngle, 1, 18, hintDivide},
+		{"Rotation((0 0), 2ft)", ErrBadAngle, 1, 18, hintConstant},
+		{"Power(Translation(<1 0>), 1.5)", ErrBadNumber, 1, 27, hintInteger},
+		{
+			"Translation(<1 0>)\nRotation((0 0) 1)", ErrBadTransformation,
+			2, 16, "arguments are separated by ',' and end with ')'",
+		},
+		{
+			"LineReflection({(0 0) (0 0)})", geometry.ErrNoLine, 1, 16,
+			hintNoLine,
+		},
+		{"Translation(<1 0 0>)", ErrBadVector, 1, 18, hintVector},
+		{"Translation((1 0))", ErrBadVector, 1, 13, hintVector},
+		{"let = 1", ErrBadTransformation, 1, 5, hintLet},
+		{"include other.txt", ErrBadTransformation, 1, 9, hintInclude},
+		{
+			"Translation(<1 0>) Translation(<0 1>)", ErrBadTransformation,
+			1, 20, "each line holds one transformation",
+		},
+		{
+			"  Compose(\n\tTranslation(<1 0>),\n\tRotation((0 x), 1),\n)",
+			ErrBadPoint, 3, 14, undefined("x"),
+		},
+		{"Translation(<é 0>)", ErrBadVector, 1, 14, undefined("é")},
+	}
+	for _, c := range cases {
+		_, err := Transformation(strings.NewReader(c.x))
+		checkAt(t, c.x, err, c.cause, c.line, c.column)
+		var e *Error
+		if !errors.As(err, &e) {
+			continue
+		}
+		if e.Hint != c.hint {
+			t.Errorf("%q gives hint %q but should give %q", c.x, e.Hint, c.hint)
+		}
+		if want := strings.Split(c.x, "\n")[c.line-1]; e.Snippet != want {
+			t.Errorf("%q gives snippet %q but should give %q",
+				c.x, e.Snippet, want)
+		}
+	}
+}
+
+// TestErrorPrimitives checks the functions parsing a single primitive give
+// *Errors at the problem including for numbers that aren't finite.
+func TestErrorPrimitives(t *testing.T) {
+	cases := []struct {
+		parse        func(string) error
+		x            string
+		cause        error
+		line, column int
+	}{
+		{parseLine, "{(0 0) (1 1)", ErrBadLine, 1, 13},
+		{parseLine, "{(0 0)}", ErrBadLine, 1, 7},
+		{parsePoint, "(1 2", ErrBadPoint, 1, 5},
+		{parsePoint, "(1 2 3)", ErrBadPoint, 1, 6},
+		{parseVector, "<1>", ErrBadVector, 1, 3},
+		{parseNumber, "1 +", ErrBadNumber, 1, 4},
+		{parseNumber, "2 * (3", ErrBadNumber, 1, 7},
+		{parseNumber, "", ErrBadNumber, 1, 1},
+		{parseNumber, "NaN", ErrBadNumber, 1, 1},
+		{parseNumber, "-Infinity", ErrBadNumber, 1, 2},
+		{parseNumber, "0x1p3", ErrBadNumber, 1, 2},
+		{parseNumber, "1e999", ErrBadNumber, 1, 1},
+		{parseNumber, "1e308*10", ErrBadNumber, 1, 6},
+		{parseNumber, "1e308tau", ErrBadNumber, 1, 1},
+		{parseAngle, "Inf", ErrBadAngle, 1, 1},
+		{parseAngle, "1e308/1e-308", ErrBadAngle, 1, 6},
+	}
+	for _, c := range cases {
+		checkAt(t, c.x, c.parse(c.x), c.cause, c.line, c.column)
+	}
+}
+
+// TestErrorString checks *Errors look like 'Line:Column: Err' and unwrap to
+// their cause.
+func TestErrorString(t *testing.T) {
+	e := errorAt(Position{Line: 3, Column: 7}, ErrBadPoint, hintPoint)
+	if got, want := e.Error(), "3:7: "+ErrBadPoint.Error(); got != want {
+		t.Errorf("%#v looks like %q but should look like %q", e, got, want)
+	}
+	if !errors.Is(e, ErrBadPoint) {
+		t.Errorf("%v doesn't unwrap to %v", e, ErrBadPoint)
+	}
+}
+
+// parseLine parses the geometry.Line x and returns the error.
+func parseLine(x string) error {
+	_, err := Line(x)
+	return err
+}
+
+// parsePoint parses the geometry.Point x and returns the error.
+func parsePoint(x string) error {
+	_, err := Point(x)
+	return err
+}
+
+// parseVector parses the geometry.Vector x and returns the error.
+func parseVector(x string) error {
+	_, err := Vector(x)
+	return err
+}
+
+// parseNumber parses the geometry.Number x and returns the error.
+func parseNumber(x string) error {
+	_, err := Number(x)
+	return err
+}
+
+// parseAngle parses the geometry.Angle x and returns the error.
+func parseAngle(x string) error {
+	_, err := Angle(x)
+	return err
+}
@@ -0,0 +1,512 @@
+package parse
+
+import (
+	"errors"
+	"io"
+	"math"
+	"math/big"
+
+	"github.com/jwowillo/viztransform/exact"
+	"github.com/jwowillo/viztransform/geometry"
+)
+
+// ErrInexactAngle is returned when a rotation's angle parsed into an
+// exact.Transformation doesn't have a rational cosine and sine.
+var ErrInexactAngle = errors.New("angle isn't a multiple of a quarter turn")
+
+// ExactTransformation parses an exact.Transformation from the io.Reader r.
+//
+// The string follows the same pattern as Transformation's except arithmetic
+// on numbers is done exactly so '1/3' is exactly a third and numbers can't use
+// the constants. Rotations must be by a multiple of a quarter turn since those
+// are the only angles with a rational cosine and sine. The constants can be
+// used in angles so a quarter turn can be written 'pi/2' or '90deg'.
+//
+// Returns the same errors as Transformation. Returns an *Error caused by
+// ErrInexactAngle if a rotation's angle isn't a multiple of a quarter turn.
+func ExactTransformation(r io.Reader) (exact.Transformation, error) {
+	f, err := ParseFile(r)
+	if err != nil {
+		return nil, err
+	}
+	var t exact.Transformation
+	p := newProgram(
+		func(x Expr) error {
+			nt, err := exactTransformation(x)
+			t = exact.Compose(t, nt)
+			return err
+		},
+		check(
+			func(x Expr) error {
+				_, err := exactTransformation(x)
+				return err
+			},
+			func(x Expr) error {
+				_, err := exactLine(x)
+				return err
+			},
+			func(x Expr) error {
+				_, err := exactPoint(x)
+				return err
+			},
+			func(x Expr) error {
+				_, err := exactVector(x)
+				return err
+			},
+		),
+	)
+	if err := p.runFile(f, ""); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// exactTransformation evaluates Expr x which is a called
+// exact.Transformation-constructor or operator.
+//
+// Returns an *Error caused by ErrBadTransformation if x isn't a CallExpr with a
+// recognized name.
+func exactTransformation(x Expr) (exact.Transformation, error) {
+	if r, ok := x.(*refExpr); ok {
+		v, err := r.value(
+			evaluation{kind: "exactTransformation"},
+			func(x Expr) (interface{}, error) { return exactTransformation(x) },
+		)
+		t, _ := v.(exact.Transformation)
+		return t, err
+	}
+	c, ok := x.(*CallExpr)
+	if !ok {
+		return nil, notCall(x)
+	}
+	switch c.Name {
+	case "NoTransformation":
+		return exactNoTransformation(c)
+	case "LineReflection":
+		return exactLineReflection(c)
+	case "Translation":
+		return exactTranslation(c)
+	case "Rotation":
+		return exactRotation(c)
+	case "GlideReflection":
+		return exactGlideReflection(c)
+	case "Compose":
+		return exactCompose(c)
+	case "Inverse":
+		return exactInverse(c)
+	case "Power":
+		return exactPower(c)
+	case "Conjugate":
+		return exactConjugate(c)
+	}
+	return nil, unknown(c)
+}
+
+// exactNoTransformation parses an exact.Transformation with
+// transform.TypeNoTransformation from constructor c.
+//
+// Returns ErrBadTransformation if any arguments are passed.
+func exactNoTransformation(c *CallExpr) (exact.Transformation, error) {
+	if err := arity(c, 0); err != nil {
+		return nil, err
+	}
+	return exact.NoTransformation(), nil
+}
+
+// exactLineReflection parses an exact.Transformation with
+// transform.TypeLineReflection from constructor c.
+//
+// Returns ErrBadTransformation if there isn't exactly 1 argument passed.
+// Returns ErrBadLine if that argument can't be parsed to an exact.Line.
+func exactLineReflection(c *CallExpr) (exact.Transformation, error) {
+	if err := arity(c, 1); err != nil {
+		return nil, err
+	}
+	l, err := exactLine(c.Args[0])
+	if err != nil {
+		return nil, err
+	}
+	return exact.LineReflection(l), nil
+}
+
+// exactTranslation parses an exact.Transformation with
+// transform.TypeTranslation from constructor c.
+//
+// Returns ErrBadTransformation if there isn't exactly 1 argument passed.
+// Returns ErrBadVector if that argument can't be parsed to an exact.Vector.
+func exactTranslation(c *CallExpr) (exact.Transformation, error) {
+	if err := arity(c, 1); err != nil {
+		return nil, err
+	}
+	v, err := exactVector(c.Args[0])
+	if err != nil {
+		return nil, err
+	}
+	return exact.Translation(v), nil
+}
+
+// exactRotation parses an exact.Transformation with transform.TypeRotation
+// from constructor c.
+//
+// Returns ErrBadTransformation if there aren't exactly 2 arguments passed.
+// Returns ErrBadPoint if the first argument can't be parsed to an exact.Point.
+// Returns ErrBadAngle if the second argument can't be parsed to a
+// geometry.Angle and ErrInexactAngle if the geometry.Angle isn't a multiple of
+// a quarter turn.
+func exactRotation(c *CallExpr) (exact.Transformation, error) {
+	if err := arity(c, 2); err != nil {
+		return nil, err
+	}
+	p, err := exactPoint(c.Args[0])
+	if err != nil {
+		return nil, err
+	}
+	rads, err := angle(c.Args[1])
+	if err != nil {
+		return nil, err
+	}
+	quarters := math.Round(float64(rads) / (math.Pi / 2))
+	if !geometry.AreEqual(
+		geometry.Number(quarters*math.Pi/2),
+		geometry.Number(rads),
+	) {
+		return nil, errorAt(c.Args[1].Pos(), ErrInexactAngle, hintInexact)
+	}
+	// The cosine and sine of every quarter turn are 0, 1, or -1 so rounding
+	// the float ones is exact.
+	cos := exact.NewNumber(int64(math.Round(math.Cos(quarters*math.Pi/2))), 1)
+	sin := exact.NewNumber(int64(math.Round(math.Sin(quarters*math.Pi/2))), 1)
+	return exact.Rotation(p, cos, sin)
+}
+
+// exactGlideReflection parses an exact.Transformation with
+// transform.TypeGlideReflection from constructor c.
+//
+// Returns ErrBadTransformation if there aren't exactly 2 arguments passed.
+// Returns ErrBadLine if the first argument can't be parsed to an exact.Line.
+// Returns ErrBadVector if the second argument can't be parsed to an
+// exact.Vector.
+func exactGlideReflection(c *CallExpr) (exact.Transformation, error) {
+	if err := arity(c, 2); err != nil {
+		return nil, err
+	}
+	l, err := exactLine(c.Args[0])
+	if err != nil {
+		return nil, err
+	}
+	v, err := exactVector(c.Args[1])
+	if err != nil {
+		return nil, err
+	}
+	return exact.GlideReflection(l, v), nil
+}
+
+// exactCompose parses the composition of the exact.Transformations passed to
+// operator c.
+//
+// Returns an error if any argument can't be parsed to an exact.Transformation.
+func exactCompose(c *CallExpr) (exact.Transformation, error) {
+	ts, err := exactTransformations(c.Args)
+	if err != nil {
+		return nil, err
+	}
+	return exact.Compose(ts...), nil
+}
+
+// exactInverse parses the inverse of the exact.Transformation passed to
+// operator c.
+//
+// Returns ErrBadTransformation if there isn't exactly 1 argument passed or it
+// can't be parsed to an exact.Transformation.
+func exactInverse(c *CallExpr) (exact.Transformation, error) {
+	if err := arity(c, 1); err != nil {
+		return nil, err
+	}
+	t, err := exactTransformation(c.Args[0])
+	if err != nil {
+		return nil, err
+	}
+	return exact.Inverse(t), nil
+}
+
+// exactPower parses the power of the exact.Transformation passed to operator
+// c.
+//
+// Returns ErrBadTransformation if there aren't exactly 2 arguments passed or
+// the first can't be parsed to an exact.Transformation. Returns ErrBadNumber
+// if the second isn't an integer.
+func exactPower(c *CallExpr) (exact.Transformation, error) {
+	if err := arity(c, 2); err != nil {
+		return nil, err
+	}
+	t, err := exactTransformation(c.Args[0])
+	if err != nil {
+		return nil, err
+	}
+	n, err := integer(c.Args[1])
+	if err != nil {
+		return nil, err
+	}
+	return exact.Power(t, n), nil
+}
+
+// exactConjugate parses the conjugate of the first exact.Transformation
+// passed to operator c by the second.
+//
+// Returns ErrBadTransformation if there aren't exactly 2 arguments passed or
+// they can't be parsed to exact.Transformations.
+func exactConjugate(c *CallExpr) (exact.Transformation, error) {
+	if err := arity(c, 2); err != nil {
+		return nil, err
+	}
+	ts, err := exactTransformations(c.Args)
+	if err != nil {
+		return nil, err
+	}
+	return exact.Conjugate(ts[0], ts[1]), nil
+}
+
+// exactTransformations parses each of Exprs xs to an exact.Transformation.
+func exactTransformations(xs []Expr) ([]exact.Transformation, error) {
+	ts := make([]exact.Transformation, len(xs))
+	for i, x := range xs {
+		t, err := exactTransformation(x)
+		if err != nil {
+			return nil, err
+		}
+		ts[i] = t
+	}
+	return ts, nil
+}
+
+// ExactLine parses an exact.Line from the string x.
+//
+// Returns an *Error caused by ErrBadLine if the string doesn't fit the
+// exact.Line string-representation pattern.
+func ExactLine(x string) (exact.Line, error) {
+	e, err := parseExpr(x)
+	if err != nil {
+		err = recause(err, start, ErrBadLine, hintLine)
+		return exact.Line{}, withSnippet(err, x)
+	}
+	l, err := exactLine(e)
+	return l, withSnippet(err, x)
+}
+
+// exactLine evaluates Expr x to an exact.Line.
+//
+// Returns an *Error caused by ErrBadLine if x isn't a LineExpr of two
+// exact.Points and by geometry.ErrNoLine if the exact.Points are the same.
+func exactLine(x Expr) (exact.Line, error) {
+	if r, ok := x.(*refExpr); ok {
+		v, err := r.value(
+			evaluation{kind: "exactLine"},
+			func(x Expr) (interface{}, error) { return exactLine(x) },
+		)
+		l, _ := v.(exact.Line)
+		return l, err
+	}
+	l, ok := x.(*LineExpr)
+	if !ok {
+		return exact.Line{}, mismatch(x, ErrBadLine, hintLine)
+	}
+	a, err := exactPoint(l.A)
+	if err != nil {
+		return exact.Line{}, recause(err, l.Pos(), ErrBadLine, hintLine)
+	}
+	b, err := exactPoint(l.B)
+	if err != nil {
+		return exact.Line{}, recause(err, l.Pos(), ErrBadLine, hintLine)
+	}
+	el, err := exact.NewLineFromPoints(a, b)
+	if err != nil {
+		return exact.Line{}, errorAt(l.Pos(), err, hintNoLine)
+	}
+	return el, nil
+}
+
+// ExactVector parses an exact.Vector from the string x.
+//
+// Returns an *Error caused by ErrBadVector if the string doesn't fit the
+// exact.Vector string-representation pattern.
+func ExactVector(x string) (exact.Vector, error) {
+	e, err := parseExpr(x)
+	if err != nil {
+		err = recause(err, start, ErrBadVector, hintVector)
+		return exact.Vector{}, withSnippet(err, x)
+	}
+	v, err := exactVector(e)
+	return v, withSnippet(err, x)
+}
+
+// exactVector evaluates Expr x to an exact.Vector.
+//
+// Returns an *Error caused by ErrBadVector if x isn't a VectorExpr of two
+// exact.Numbers.
+func exactVector(x Expr) (exact.Vector, error) {
+	if r, ok := x.(*refExpr); ok {
+		v, err := r.value(
+			evaluation{kind: "exactVector"},
+			func(x Expr) (interface{}, error) { return exactVector(x) },
+		)
+		vec, _ := v.(exact.Vector)
+		return vec, err
+	}
+	v, ok := x.(*VectorExpr)
+	if !ok {
+		return exact.Vector{}, mismatch(x, ErrBadVector, hintVector)
+	}
+	i, err := exactNumber(v.I)
+	if err != nil {
+		return exact.Vector{}, recause(err, v.Pos(), ErrBadVector, hintVector)
+	}
+	j, err := exactNumber(v.J)
+	if err != nil {
+		return exact.Vector{}, recause(err, v.Pos(), ErrBadVector, hintVector)
+	}
+	return exact.Vector{I: i, J: j}, nil
+}
+
+// ExactPoint parses an exact.Point from the string x.
+//
+// Returns an *Error caused by ErrBadPoint if the string doesn't fit the
+// exact.Point string-representation pattern.
+func ExactPoint(x string) (exact.Point, error) {
+	e, err := parseExpr(x)
+	if err != nil {
+		err = recause(err, start, ErrBadPoint, hintPoint)
+		return exact.Point{}, withSnippet(err, x)
+	}
+	p, err := exactPoint(e)
+	return p, withSnippet(err, x)
+}
+
+// exactPoint evaluates Expr x to an exact.Point.
+//
+// Returns an *Error caused by ErrBadPoint if x isn't a PointExpr of two
+// exact.Numbers.
+func exactPoint(x Expr) (exact.Point, error) {
+	if r, ok := x.(*refExpr); ok {
+		v, err := r.value(
+			evaluation{kind: "exactPoint"},
+			func(x Expr) (interface{}, error) { return exactPoint(x) },
+		)
+		p, _ := v.(exact.Point)
+		return p, err
+	}
+	p, ok := x.(*PointExpr)
+	if !ok {
+		return exact.Point{}, mismatch(x, ErrBadPoint, hintPoint)
+	}
+	nx, err := exactNumber(p.X)
+	if err != nil {
+		return exact.Point{}, recause(err, p.Pos(), ErrBadPoint, hintPoint)
+	}
+	ny, err := exactNumber(p.Y)
+	if err != nil {
+		return exact.Point{}, recause(err, p.Pos(), ErrBadPoint, hintPoint)
+	}
+	return exact.Point{X: nx, Y: ny}, nil
+}
+
+// ExactNumber parses an exact.Number from the string x.
+//
+// The string is a number as described by ParseFile like '1/3' or '0.25' which
+// is read exactly instead of being rounded to a float64. It can't use the
+// constants since they aren't rational.
+//
+// Returns an *Error caused by ErrBadNumber if the string isn't a number.
+func ExactNumber(x string) (exact.Number, error) {
+	if r, ok := new(big.Rat).SetString(x); ok {
+		return exact.NewNumberFromRat(r), nil
+	}
+	e, err := parseExpr(x)
+	if err != nil {
+		err = recause(err, start, ErrBadNumber, hintExact)
+		return exact.Number{}, withSnippet(err, x)
+	}
+	n, err := exactNumber(e)
+	return n, withSnippet(err, x)
+}
+
+// exactNumber evaluates Expr x to an exact.Number.
+//
+// Returns an *Error caused by ErrBadNumber if x isn't a number, uses a
+// constant, or divides by 0.
+func exactNumber(x Expr) (exact.Number, error) {
+	r, err := rat(x)
+	if err != nil {
+		return exact.Number{}, err
+	}
+	return exact.NewNumberFromRat(r), nil
+}
+
+// rat evaluates Expr x to a big.Rat.
+//
+// Returns the same errors as exactNumber.
+func rat(x Expr) (*big.Rat, error) {
+	switch x := x.(type) {
+	case *NumberExpr:
+		if x.Unit != "" {
+			p := after(x.Pos(), len(x.Value))
+			return nil, problemAt(p, ErrBadNumber, hintExactConstant)
+		}
+		r, ok := new(big.Rat).SetString(x.Value)
+		if !ok {
+			return nil, errorAt(x.Pos(), ErrBadNumber, hintExact)
+		}
+		return r, nil
+	case *refExpr:
+		v, err := x.value(
+			evaluation{kind: "rat"},
+			func(x Expr) (interface{}, error) { return rat(x) },
+		)
+		r, _ := v.(*big.Rat)
+		return r, err
+	case *IdentExpr:
+		if _, ok := constants[x.Name]; !ok {
+			return nil, problemAt(x.Pos(), ErrBadNumber, undefined(x.Name))
+		}
+		return nil, problemAt(x.Pos(), ErrBadNumber, hintExactConstant)
+	case *ParenExpr:
+		return rat(x.X)
+	case *UnaryExpr:
+		r, err := rat(x.X)
+		if err != nil {
+			return nil, err
+		}
+		if x.Op == "-" {
+			r.Neg(r)
+		}
+		return r, nil
+	case *BinaryExpr:
+		return binaryRat(x)
+	}
+	return nil, errorAt(x.Pos(), ErrBadNumber, hintExact)
+}
+
+// binaryRat evaluates BinaryExpr x to a big.Rat.
+//
+// Returns the same errors as exactNumber.
+func binaryRat(x *BinaryExpr) (*big.Rat, error) {
+	a, err := rat(x.X)
+	if err != nil {
+		return nil, err
+	}
+	b, err := rat(x.Y)
+	if err != nil {
+		return nil, err
+	}
+	switch x.Op {
+	case "+":
+		return a.Add(a, b), nil
+	case "-":
+		return a.Sub(a, b), nil
+	case "*":
+		return a.Mul(a, b), nil
+	}
+	if b.Sign() == 0 {
+		return nil, problemAt(x.OpPos, ErrBadNumber, hintDivide)
+	}
+	return a.Quo(a, b), nil
+}
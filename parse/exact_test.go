@@ -0,0 +1,119 @@
+package parse
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/jwowillo/viztransform/exact"
+	"github.com/jwowillo/viztransform/geometry"
+	"github.com/jwowillo/viztransform/transform"
+)
+
+// TestExactNumber checks numbers are parsed exactly where the float path
+// rounds them.
+func TestExactNumber(t *testing.T) {
+	cases := []struct {
+		x    string
+		want *big.Rat
+	}{
+		{"1/3", big.NewRat(1, 3)},
+		{"1/3*3", big.NewRat(1, 1)},
+		{"0.1+0.2", big.NewRat(3, 10)},
+		{"-(1/2 - 1/3)", big.NewRat(-1, 6)},
+		{"2.5e-1", big.NewRat(1, 4)},
+		{"1 - -2", big.NewRat(3, 1)},
+	}
+	for _, c := range cases {
+		got, err := ExactNumber(c.x)
+		if err != nil {
+			t.Errorf("%q gives %v", c.x, err)
+			continue
+		}
+		if got.Rat().Cmp(c.want) != 0 {
+			t.Errorf("%q gives %v but should give %v", c.x, got, c.want)
+		}
+		n, err := Number(c.x)
+		if err != nil {
+			t.Errorf("%q gives %v", c.x, err)
+			continue
+		}
+		if !geometry.AreEqual(n, got.Float()) {
+			t.Errorf("%q gives %v on the float path but %v exactly",
+				c.x, n, got)
+		}
+	}
+}
+
+// TestExactTransformation checks exact.Transformations parsed from rational
+// inputs simplify to the same type and map geometry.Points the same as the
+// transform.Transformations parsed from the same string.
+func TestExactTransformation(t *testing.T) {
+	cases := []string{
+		"NoTransformation()",
+		"Translation(<1/3 2/3>)",
+		"LineReflection({(0 0) (1/3 1)})",
+		"Rotation((1/2 1/3), pi/2)",
+		"Rotation((0 0), 90deg)\nRotation((0 0), -pi)",
+		"GlideReflection({(0 0) (1 1)}, <1/7 1/7>)",
+		"Compose(LineReflection({(0 0) (1 0)}), " +
+			"LineReflection({(0 1/3) (1 1/3)}))",
+		"Compose(LineReflection({(0 0) (1 0)}), " +
+			"LineReflection({(0 0) (1 1)}))",
+		"Inverse(Rotation((1 2), pi/2))",
+		"Power(GlideReflection({(0 0) (0 1)}, <0 1/3>), 3)",
+		"Conjugate(Translation(<1 0>), Rotation((0 0), 3pi/2))",
+		"let v = <0.1 0.2>\nTranslation(v)\nTranslation(v)",
+	}
+	ps := []geometry.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0.25, Y: -3}}
+	for _, x := range cases {
+		e, err := ExactTransformation(strings.NewReader(x))
+		if err != nil {
+			t.Errorf("%q gives %v", x, err)
+			continue
+		}
+		f, err := Transformation(strings.NewReader(x))
+		if err != nil {
+			t.Errorf("%q gives %v", x, err)
+			continue
+		}
+		s := exact.Simplify(e)
+		if got, want := exact.TypeOf(s), transform.TypeOf(f); got != want {
+			t.Errorf("%q is a %v exactly but a %v on the float path",
+				x, got, want)
+		}
+		for _, p := range ps {
+			ep, _ := exact.NewNumberFromFloat(p.X)
+			eq, _ := exact.NewNumberFromFloat(p.Y)
+			got := exact.Apply(s, exact.Point{X: ep, Y: eq}).Float()
+			want := transform.Apply(f, p)
+			if !geometry.AreSamePoint(got, want) {
+				t.Errorf("%q maps %v to %v exactly but to %v on the float "+
+					"path", x, p, got, want)
+			}
+		}
+	}
+}
+
+// TestExactTransformationBad checks strings the float path can read but that
+// aren't exact give *Errors at the problem with a hint saying why.
+func TestExactTransformationBad(t *testing.T) {
+	cases := []struct {
+		x            string
+		err          error
+		line, column int
+		hint         string
+	}{
+		{"Rotation((0 0), pi/3)", ErrInexactAngle, 1, 17, hintInexact},
+		{"Translation(<pi 0>)", ErrBadVector, 1, 14, hintExactConstant},
+		{"Translation(<2deg 0>)", ErrBadVector, 1, 15, hintExactConstant},
+		{"Translation(<1/(1-1) 0>)", ErrBadVector, 1, 15, hintDivide},
+	}
+	for _, c := range cases {
+		_, err := ExactTransformation(strings.NewReader(c.x))
+		checkAt(t, c.x, err, c.err, c.line, c.column)
+		if e, ok := err.(*Error); ok && e.Hint != c.hint {
+			t.Errorf("%q gives hint %q but should give %q", c.x, e.Hint, c.hint)
+		}
+	}
+}
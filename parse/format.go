@@ -0,0 +1,288 @@
+package parse
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jwowillo/viztransform/geometry"
+	"github.com/jwowillo/viztransform/transform"
+)
+
+// Format writes transform.Transformation t to io.Writer w so that
+// Transformation reads back exactly t.
+//
+// Unlike the String-representation of t, t isn't simplified and numbers aren't
+// rounded. Each geometry.Line of t is written on its own line as a
+// 'LineReflection' of the 2 geometry.Points the geometry.Line was created from
+// with every number written with as many digits as it takes to read it back
+// exactly. 'NoTransformation()' is written if t has no geometry.Lines.
+func Format(w io.Writer, t transform.Transformation) error {
+	if len(t) == 0 {
+		_, err := fmt.Fprintln(w, "NoTransformation()")
+		return err
+	}
+	for _, l := range t {
+		a, b := l.Points()
+		_, err := fmt.Fprintf(
+			w,
+			"LineReflection({%s %s})\n",
+			FormatPoint(a),
+			FormatPoint(b),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FormatPoint returns geometry.Point p like '(x y)' without rounding so that
+// Point reads back exactly p.
+func FormatPoint(p geometry.Point) string {
+	return fmt.Sprintf("(%s %s)", FormatNumber(p.X), FormatNumber(p.Y))
+}
+
+// FormatNumber returns geometry.Number x with the fewest digits that Number
+// reads back exactly.
+//
+// Unlike the String-representation of x, x isn't rounded to 32 bits and tiny
+// numbers aren't written as 0.
+func FormatNumber(x geometry.Number) string {
+	return strconv.FormatFloat(float64(x), 'g', -1, 64)
+}
+
+// FormatFile writes the File f to io.Writer w in the canonical format.
+//
+// Every top-level expression is written on its own line with a single space
+// after each ',', between the parts of geometry.Lines, geometry.Points, and
+// geometry.Vectors, and between the parts of definitions and includes and no
+// other spaces, even around operators, except before a '-' sign that follows
+// an operator like '1- -2'. Numbers keep their value but lose '+' signs,
+// unneeded zeros, and exponents of 0. A call that was split over lines is
+// written with each argument on its own line indented by a tab and followed by
+// a ',' and its ')' on the line after the last argument. Comments stay on the
+// line they were on or before the expression they were in and single blank
+// lines between expressions and comments are kept.
+func FormatFile(w io.Writer, f *File) error {
+	p := &printer{comments: f.Comments}
+	for _, x := range f.Exprs {
+		p.leading(x.Pos(), 0)
+		p.expr(x, 0)
+		p.trailing(x.End())
+		p.b.WriteString("\n")
+	}
+	p.comment(Position{Line: int(^uint(0) >> 1)}, 0)
+	_, err := io.WriteString(w, p.b.String())
+	return err
+}
+
+// printer of a File in the canonical format.
+type printer struct {
+	b strings.Builder
+	// comments that haven't been written yet.
+	comments []Comment
+	// line is the last line of the File written so far or 0 if nothing has
+	// been.
+	line int
+}
+
+// leading writes the comments before Position pos on their own lines indented
+// depth times followed by a blank line if there was one before pos.
+func (p *printer) leading(pos Position, depth int) {
+	p.comment(pos, depth)
+	p.gap(pos.Line)
+}
+
+// comment writes the comments before Position pos on their own lines indented
+// depth times.
+func (p *printer) comment(pos Position, depth int) {
+	for len(p.comments) > 0 && before(p.comments[0].Pos, pos) {
+		c := p.comments[0]
+		p.comments = p.comments[1:]
+		p.gap(c.Pos.Line)
+		p.indent(depth)
+		p.b.WriteString(strings.TrimRight(c.Text, " \t\r"))
+		p.b.WriteString("\n")
+		if c.Pos.Line > p.line {
+			p.line = c.Pos.Line
+		}
+	}
+}
+
+// trailing writes the comment on the same line as Position end after end and
+// marks the line as written.
+func (p *printer) trailing(end Position) {
+	if len(p.comments) > 0 && p.comments[0].Pos.Line == end.Line {
+		p.b.WriteString(" ")
+		p.b.WriteString(strings.TrimRight(p.comments[0].Text, " \t\r"))
+		p.comments = p.comments[1:]
+	}
+	p.line = end.Line
+}
+
+// gap writes a blank line if there is one between the last line written and
+// line n.
+func (p *printer) gap(n int) {
+	if p.line != 0 && n > p.line+1 {
+		p.b.WriteString("\n")
+	}
+}
+
+// indent writes depth tabs.
+func (p *printer) indent(depth int) {
+	p.b.WriteString(strings.Repeat("\t", depth))
+}
+
+// expr writes Expr x which is indented depth times if it's split over lines.
+func (p *printer) expr(x Expr, depth int) {
+	switch x := x.(type) {
+	case *CallExpr:
+		p.call(x, depth)
+	case *LineExpr:
+		p.pair("{", x.A, x.B, "}", depth)
+	case *PointExpr:
+		p.pair("(", x.X, x.Y, ")", depth)
+	case *VectorExpr:
+		p.pair("<", x.I, x.J, ">", depth)
+	case *NumberExpr:
+		p.b.WriteString(canonicalNumber(x.Value))
+		p.b.WriteString(x.Unit)
+	case *IdentExpr:
+		p.b.WriteString(x.Name)
+	case *ParenExpr:
+		p.b.WriteString("(")
+		p.expr(x.X, depth)
+		p.b.WriteString(")")
+	case *UnaryExpr:
+		if x.Op == "-" {
+			if p.afterOperator() {
+				p.b.WriteString(" ")
+			}
+			p.b.WriteString("-")
+		}
+		p.expr(x.X, depth)
+	case *BinaryExpr:
+		p.expr(x.X, depth)
+		p.b.WriteString(x.Op)
+		p.expr(x.Y, depth)
+	case *LetExpr:
+		fmt.Fprintf(&p.b, "let %s = ", x.Name)
+		p.expr(x.Value, depth)
+	case *IncludeExpr:
+		fmt.Fprintf(&p.b, "include %s", strconv.Quote(x.Path))
+	}
+}
+
+// afterOperator returns true if the last character written is an operator so
+// a '-' written next needs a space before it to read back as a sign.
+func (p *printer) afterOperator() bool {
+	x := p.b.String()
+	return x != "" && strings.IndexByte("+-*/", x[len(x)-1]) >= 0
+}
+
+// call writes CallExpr x on one line if it was on one line and with each
+// argument on its own line indented depth+1 times otherwise.
+func (p *printer) call(x *CallExpr, depth int) {
+	p.b.WriteString(x.Name)
+	p.b.WriteString("(")
+	if x.Pos().Line == x.End().Line {
+		for i, arg := range x.Args {
+			if i > 0 {
+				p.b.WriteString(", ")
+			}
+			p.expr(arg, depth)
+		}
+		p.b.WriteString(")")
+		return
+	}
+	p.line = x.Pos().Line
+	p.trailing(x.Pos())
+	p.b.WriteString("\n")
+	for _, arg := range x.Args {
+		p.leading(arg.Pos(), depth+1)
+		p.indent(depth + 1)
+		p.expr(arg, depth+1)
+		p.b.WriteString(",")
+		p.trailing(arg.End())
+		p.b.WriteString("\n")
+	}
+	p.comment(x.Rparen, depth+1)
+	p.indent(depth)
+	p.b.WriteString(")")
+}
+
+// pair writes the Exprs a and b between brackets open and close.
+func (p *printer) pair(open string, a, b Expr, close string, depth int) {
+	p.b.WriteString(open)
+	p.expr(a, depth)
+	p.b.WriteString(" ")
+	p.expr(b, depth)
+	p.b.WriteString(close)
+}
+
+// before returns true if Position p is before Position q.
+func before(p, q Position) bool {
+	return p.Line < q.Line || p.Line == q.Line && p.Column < q.Column
+}
+
+// canonicalNumber returns the unsigned number x with the same value written
+// without leading zeros, trailing zeros after the '.', or an exponent of 0.
+//
+// x is returned as is if it isn't a number so bad numbers are left for
+// evaluating to report.
+func canonicalNumber(x string) string {
+	mantissa, exponent := x, ""
+	hasExponent := false
+	if i := strings.IndexAny(x, "eE"); i != -1 {
+		mantissa, exponent, hasExponent = x[:i], x[i+1:], true
+	}
+	whole, fraction := mantissa, ""
+	if i := strings.IndexByte(mantissa, '.'); i != -1 {
+		whole, fraction = mantissa[:i], mantissa[i+1:]
+	}
+	if whole == "" && fraction == "" || !isDigits(whole) || !isDigits(fraction) {
+		return x
+	}
+	out := trimZeros(whole)
+	if fraction = strings.TrimRight(fraction, "0"); fraction != "" {
+		out += "." + fraction
+	}
+	if !hasExponent {
+		return out
+	}
+	sign := ""
+	if exponent != "" && (exponent[0] == '+' || exponent[0] == '-') {
+		if exponent[0] == '-' {
+			sign = "-"
+		}
+		exponent = exponent[1:]
+	}
+	if exponent == "" || !isDigits(exponent) {
+		return x
+	}
+	if exponent = trimZeros(exponent); exponent != "0" {
+		out += "e" + sign + exponent
+	}
+	return out
+}
+
+// trimZeros returns the digits x without leading zeros or '0' if every digit
+// is a zero.
+func trimZeros(x string) string {
+	if x = strings.TrimLeft(x, "0"); x == "" {
+		return "0"
+	}
+	return x
+}
+
+// isDigits returns true if every character of x is a decimal digit.
+func isDigits(x string) bool {
+	for i := 0; i < len(x); i++ {
+		if !isDigit(x[i]) {
+			return false
+		}
+	}
+	return true
+}
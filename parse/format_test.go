@@ -0,0 +1,53 @@
+package parse
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestFormatFile checks every file in testdata/format is formatted like its
+// golden file and that formatting the golden file doesn't change it.
+func TestFormatFile(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "format", "*.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no files")
+	}
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			golden := strings.TrimSuffix(path, ".txt") + ".golden"
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, name := range []string{path, golden} {
+				src, err := os.ReadFile(name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := formatString(t, string(src)); got != string(want) {
+					t.Errorf("%s is formatted as\n%s\nbut should be\n%s",
+						name, got, want)
+				}
+			}
+		})
+	}
+}
+
+// formatString returns the string src formatted by FormatFile.
+func formatString(t *testing.T, src string) string {
+	t.Helper()
+	f, err := ParseFile(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := FormatFile(&b, f); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
@@ -0,0 +1,375 @@
+package parse
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jwowillo/viztransform/geometry"
+	"github.com/jwowillo/viztransform/transform"
+)
+
+// ErrBadJSON is returned when JSON doesn't hold what's being parsed.
+var ErrBadJSON = errors.New("bad JSON")
+
+// JSON parses a transform.Transformation from the JSON read from io.Reader r.
+//
+// The JSON is a JSON-object or a JSON-array of JSON-objects that are composed
+// in order. Each JSON-object is either a geometry.Line like
+// '{"a": {"x": 0, "y": 0}, "b": {"x": 1, "y": 0}}' to reflect across like the
+// elements of a transform.Transformation's JSON or a JSON-object like a
+// transform.Params' JSON like '{"type": "Translation", "vector": {"i": 1,
+// "j": 2}}'. Rotation angles are in radians.
+//
+// Returns an *Error caused by ErrBadJSON if the JSON is bad or doesn't hold
+// a transform.Transformation and by geometry.ErrNoLine if a geometry.Line's
+// Points are the same. The *Error is at the value with the problem, at the
+// JSON-object missing a field, or where the JSON stops being JSON.
+func JSON(r io.Reader) (transform.Transformation, error) {
+	return JSONWithin(r, geometry.DefaultTolerance())
+}
+
+// JSONWithin is JSON where geometry.Numbers are compared within
+// geometry.Tolerance tol when checking if geometry.Lines and
+// transform.Transformations are degenerate.
+func JSONWithin(
+	r io.Reader,
+	tol geometry.Tolerance,
+) (transform.Transformation, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	t, err := jsonTransformation(src, tol)
+	return t, withSnippet(err, string(src))
+}
+
+// jsonTransformation parses a transform.Transformation from the JSON src.
+func jsonTransformation(
+	src []byte,
+	tol geometry.Tolerance,
+) (transform.Transformation, error) {
+	if err := json.Unmarshal(src, new(json.RawMessage)); err != nil {
+		return nil, jsonError(err, src, nil, ErrBadJSON, hintJSON)
+	}
+	off := skip(src, 0)
+	switch src[off] {
+	case '{':
+		return jsonObject(src[off:], offsetPosition(src, off), tol)
+	case '[':
+	default:
+		return nil, errorAt(offsetPosition(src, off), ErrBadJSON, hintJSON)
+	}
+	d := json.NewDecoder(bytes.NewReader(src))
+	if _, err := d.Token(); err != nil {
+		return nil, jsonError(err, src, nil, ErrBadJSON, hintJSON)
+	}
+	var t transform.Transformation
+	for d.More() {
+		off := skip(src, int(d.InputOffset()))
+		var x json.RawMessage
+		if err := d.Decode(&x); err != nil {
+			return nil, jsonError(err, src, nil, ErrBadJSON, hintJSON)
+		}
+		nt, err := jsonObject(x, offsetPosition(src, off), tol)
+		if err != nil {
+			return nil, err
+		}
+		t = transform.Compose(t, nt)
+	}
+	return t, nil
+}
+
+// jsonObject parses a transform.Transformation from the JSON-object x at
+// Position p.
+//
+// Returns an *Error caused by ErrBadJSON if x isn't a geometry.Line or a
+// transform.Params' JSON-object with all of its fields. The *Error is at the
+// value of the field with the problem or at p if a field is missing.
+func jsonObject(
+	x []byte,
+	p Position,
+	tol geometry.Tolerance,
+) (transform.Transformation, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(x, &fields); err != nil {
+		return nil, errorAt(p, ErrBadJSON, hintJSON)
+	}
+	var v struct {
+		Type   *string
+		A      *geometry.Point
+		B      *geometry.Point
+		Line   *jsonLine
+		Vector *geometry.Vector
+		Center *geometry.Point
+		Angle  *float64
+		Axis   *jsonLine
+	}
+	targets := []struct {
+		name string
+		v    interface{}
+	}{
+		{"type", &v.Type},
+		{"a", &v.A},
+		{"b", &v.B},
+		{"line", &v.Line},
+		{"vector", &v.Vector},
+		{"center", &v.Center},
+		{"angle", &v.Angle},
+		{"axis", &v.Axis},
+	}
+	for _, target := range targets {
+		raw, ok := fields[target.name]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(raw, target.v); err != nil {
+			path := []string{target.name}
+			e := jsonError(err, x, path, ErrBadJSON, hintJSON)
+			q := within(p, Position{Line: e.Line, Column: e.Column})
+			e.Line, e.Column = q.Line, q.Column
+			return nil, e
+		}
+	}
+	at := func(name string) Position {
+		return within(p, offsetPosition(x, valueOffset(x, name)))
+	}
+	if v.Type == nil {
+		if v.A == nil || v.B == nil {
+			return nil, errorAt(p, ErrBadJSON, hintJSON)
+		}
+		l, err := jsonLine{A: v.A, B: v.B}.line(p, tol)
+		if err != nil {
+			return nil, err
+		}
+		return transform.LineReflection(l), nil
+	}
+	need, ok := jsonFields[*v.Type]
+	if !ok {
+		return nil, problemAt(at("type"), ErrBadJSON, hintJSONType)
+	}
+	for _, field := range need {
+		if x, ok := fields[field]; !ok || string(x) == "null" {
+			return nil, problemAt(p, ErrBadJSON, needs(*v.Type, need))
+		}
+	}
+	switch *v.Type {
+	case "LineReflection":
+		l, err := v.Line.line(at("line"), tol)
+		if err != nil {
+			return nil, err
+		}
+		return transform.LineReflection(l), nil
+	case "Translation":
+		return transform.TranslationWithin(*v.Vector, tol), nil
+	case "Rotation":
+		return transform.RotationWithin(
+			*v.Center,
+			geometry.Angle(*v.Angle),
+			tol,
+		), nil
+	case "GlideReflection":
+		l, err := v.Axis.line(at("axis"), tol)
+		if err != nil {
+			return nil, err
+		}
+		return transform.GlideReflectionWithin(l, *v.Vector, tol), nil
+	}
+	return transform.NoTransformation(), nil
+}
+
+// jsonFields are the fields each type of transform.Params' JSON-object needs
+// besides "type".
+var jsonFields = map[string][]string{
+	"NoTransformation": nil,
+	"LineReflection":   {"line"},
+	"Translation":      {"vector"},
+	"Rotation":         {"center", "angle"},
+	"GlideReflection":  {"axis", "vector"},
+}
+
+// needs returns the hint for a JSON-object with "type" name missing some of
+// fields.
+func needs(name string, fields []string) string {
+	quoted := make([]string, len(fields))
+	for i, field := range fields {
+		quoted[i] = fmt.Sprintf("\"%s\"", field)
+	}
+	return fmt.Sprintf(
+		"\"%s\" objects need %s",
+		name,
+		strings.Join(quoted, " and "),
+	)
+}
+
+// jsonLine is a geometry.Line's JSON-object which is decoded to a
+// geometry.Line within a geometry.Tolerance once it's known to have both
+// geometry.Points.
+type jsonLine struct {
+	A *geometry.Point `json:"a"`
+	B *geometry.Point `json:"b"`
+}
+
+// line returns the geometry.Line through the geometry.Points of jsonLine l
+// created within geometry.Tolerance tol.
+//
+// Returns an *Error at Position p caused by ErrBadJSON if l is missing a
+// geometry.Point and by geometry.ErrNoLine if they're the same.
+func (l jsonLine) line(
+	p Position,
+	tol geometry.Tolerance,
+) (geometry.Line, error) {
+	if l.A == nil || l.B == nil {
+		return geometry.Line{}, problemAt(p, ErrBadJSON, hintJSONLine)
+	}
+	gl, err := tol.NewLineFromPoints(*l.A, *l.B)
+	if err != nil {
+		return geometry.Line{}, errorAt(p, err, hintNoLine)
+	}
+	return gl, nil
+}
+
+// JSONPoint parses a geometry.Point from the JSON-object x like
+// '{"x": 1, "y": 2}'.
+//
+// Returns an *Error caused by ErrBadPoint if x isn't a geometry.Point's
+// JSON-object.
+func JSONPoint(x string) (geometry.Point, error) {
+	var p geometry.Point
+	if err := json.Unmarshal([]byte(x), &p); err != nil {
+		err = jsonError(err, []byte(x), nil, ErrBadPoint, hintJSONPoint)
+		return geometry.Point{}, withSnippet(err, x)
+	}
+	return p, nil
+}
+
+// jsonError returns an *Error caused by cause for the error err from decoding
+// the value at path in the JSON src.
+//
+// path is the names of the fields leading to the value from the JSON-object src
+// is or empty if the value is all of src. The *Error is where the JSON stops
+// being JSON with a hint saying why if err is a syntax error. It's otherwise at
+// the value with the wrong type with a hint saying which field it is if that's
+// known and at the value at path with hint if it isn't. Blank src stops being
+// JSON at its start.
+func jsonError(
+	err error,
+	src []byte,
+	path []string,
+	cause error,
+	hint string,
+) *Error {
+	var se *json.SyntaxError
+	if errors.As(err, &se) {
+		off := int(se.Offset) - 1
+		if end := len(bytes.TrimRight(src, " \t\r\n")); off >= end {
+			off = end
+		}
+		if off < 0 {
+			off = 0
+		}
+		return problemAt(offsetPosition(src, off), cause, se.Error())
+	}
+	var te *json.UnmarshalTypeError
+	if errors.As(err, &te) && te.Field != "" {
+		path = append(path, strings.Split(te.Field, ".")...)
+	}
+	p := offsetPosition(src, valueOffset(src, path...))
+	if te != nil && len(path) > 0 {
+		hint := fmt.Sprintf(
+			"\"%s\" has the wrong type",
+			strings.Join(path, "."),
+		)
+		return problemAt(p, cause, hint)
+	}
+	if errors.Is(err, geometry.ErrMissingField) {
+		return problemAt(p, cause, err.Error())
+	}
+	if errors.Is(err, geometry.ErrNoLine) {
+		return errorAt(p, err, hintNoLine)
+	}
+	return errorAt(p, cause, hint)
+}
+
+// valueOffset returns the offset in the JSON src of the value at the path of
+// field names from the JSON-object src is.
+//
+// The offset of the last value found is returned if a field on the path is
+// missing and the offset of all of src if path is empty.
+func valueOffset(src []byte, path ...string) int {
+	off := skip(src, 0)
+	for _, name := range path {
+		next, ok := fieldOffset(src, off, name)
+		if !ok {
+			break
+		}
+		off = next
+	}
+	return off
+}
+
+// fieldOffset returns the offset in the JSON src of the value of the field
+// called name in the JSON-object at offset off and true if the field is there.
+func fieldOffset(src []byte, off int, name string) (int, bool) {
+	d := json.NewDecoder(bytes.NewReader(src[off:]))
+	if t, err := d.Token(); err != nil || t != json.Delim('{') {
+		return 0, false
+	}
+	for d.More() {
+		key, err := d.Token()
+		if err != nil {
+			return 0, false
+		}
+		value := off + int(d.InputOffset())
+		for value < len(src) && isSeparator(src[value]) {
+			value++
+		}
+		if key == name {
+			return value, true
+		}
+		if err := d.Decode(new(json.RawMessage)); err != nil {
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+// skip returns the offset of the first byte in src at or after offset off
+// that isn't whitespace or a ','.
+func skip(src []byte, off int) int {
+	for off < len(src) && strings.IndexByte(" \t\r\n,", src[off]) >= 0 {
+		off++
+	}
+	return off
+}
+
+// isSeparator returns true if the byte b is whitespace or the ':' between a
+// JSON-object's field name and its value.
+func isSeparator(b byte) bool {
+	return strings.IndexByte(" \t\r\n:", b) >= 0
+}
+
+// within returns the Position in a string of Position q in a part of the string
+// that starts at Position p.
+func within(p, q Position) Position {
+	if q.Line == 1 {
+		return Position{Line: p.Line, Column: p.Column + q.Column - 1}
+	}
+	return Position{Line: p.Line + q.Line - 1, Column: q.Column}
+}
+
+// offsetPosition returns the Position of the byte at offset off in src.
+func offsetPosition(src []byte, off int) Position {
+	p := start
+	for _, r := range string(src[:off]) {
+		if r == '\n' {
+			p = Position{Line: p.Line + 1, Column: 1}
+		} else {
+			p.Column++
+		}
+	}
+	return p
+}
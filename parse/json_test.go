@@ -0,0 +1,102 @@
+package parse
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jwowillo/viztransform/geometry"
+)
+
+// TestJSONBlank checks blank JSON is an *Error at its start instead of a
+// panic.
+func TestJSONBlank(t *testing.T) {
+	for _, x := range []string{"", " ", "\n"} {
+		_, err := JSON(strings.NewReader(x))
+		checkStart(t, x, err, ErrBadJSON)
+		_, err = JSONPoint(x)
+		checkStart(t, x, err, ErrBadPoint)
+	}
+}
+
+// checkStart fails the test if err from parsing x isn't an *Error at the start
+// of x caused by cause.
+func checkStart(t *testing.T, x string, err, cause error) {
+	t.Helper()
+	var e *Error
+	if !errors.As(err, &e) {
+		t.Fatalf("%q gives %v but should give an *Error", x, err)
+	}
+	if !errors.Is(err, cause) || e.Line != 1 || e.Column != 1 {
+		t.Errorf("%q gives %v at %d:%d but should give %v at 1:1",
+			x, err, e.Line, e.Column, cause)
+	}
+}
+
+// TestJSONFieldPosition checks problems with a field of a JSON-object are at
+// the field's value.
+func TestJSONFieldPosition(t *testing.T) {
+	cases := []struct {
+		x            string
+		cause        error
+		line, column int
+		hint         string
+	}{
+		{
+			`{"type": 3}`,
+			ErrBadJSON, 1, 10, `"type" has the wrong type`,
+		},
+		{
+			`{"type": "Spin"}`,
+			ErrBadJSON, 1, 10, hintJSONType,
+		},
+		{
+			`{"type": "Rotation", "center": {"x": 1, "y": "a"}, "angle": 1}`,
+			ErrBadJSON, 1, 46, `"center.y" has the wrong type`,
+		},
+		{
+			"[\n  {\"type\": \"Rotation\",\n   \"center\": {\"x\": 1}, \"angle\": 1}]",
+			ErrBadJSON, 3, 14, "JSON-object is missing a field 'y'",
+		},
+		{
+			`{"type": "LineReflection", "line": {"a": {"x": 0, "y": 0}}}`,
+			ErrBadJSON, 1, 36, hintJSONLine,
+		},
+		{
+			`{"type": "GlideReflection", "vector": {"i": 1, "j": 0}, ` +
+				`"axis": {"a": {"x": 0, "y": 0}, "b": {"x": 0, "y": 0}}}`,
+			geometry.ErrNoLine, 1, 65, hintNoLine,
+		},
+		{
+			`{"type": "Translation"}`,
+			ErrBadJSON, 1, 1, `"Translation" objects need "vector"`,
+		},
+	}
+	for _, c := range cases {
+		_, err := JSON(strings.NewReader(c.x))
+		var e *Error
+		if !errors.As(err, &e) {
+			t.Fatalf("%q gives %v but should give an *Error", c.x, err)
+		}
+		if !errors.Is(err, c.cause) || e.Line != c.line ||
+			e.Column != c.column || e.Hint != c.hint {
+			t.Errorf("%q gives %v with hint %q but should give %v at %d:%d "+
+				"with hint %q", c.x, err, e.Hint, c.cause, c.line, c.column,
+				c.hint)
+		}
+	}
+}
+
+// TestJSONPointPosition checks a field of a geometry.Point with the wrong type
+// is reported at its value.
+func TestJSONPointPosition(t *testing.T) {
+	x := `{"x": 1, "y": true}`
+	_, err := JSONPoint(x)
+	var e *Error
+	if !errors.As(err, &e) {
+		t.Fatalf("%q gives %v but should give an *Error", x, err)
+	}
+	if !errors.Is(err, ErrBadPoint) || e.Line != 1 || e.Column != 15 {
+		t.Errorf("%q gives %v but should give %v at 1:15", x, err, ErrBadPoint)
+	}
+}
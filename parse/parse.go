@@ -4,9 +4,11 @@
 package parse
 
 import (
-	"bufio"
 	"errors"
+	"fmt"
 	"io"
+	"math"
+	"os"
 	"strconv"
 	"strings"
 
@@ -28,6 +30,12 @@ var (
 	ErrBadNumber = errors.New("bad geometry.Number-string")
 	// ErrBadAngle is returned when a geometry.Angle's string is bad.
 	ErrBadAngle = errors.New("bad geometry.Angle-string")
+	// ErrBadPointPair is returned when a string of 2 geometry.Points is
+	// bad.
+	ErrBadPointPair = errors.New("bad geometry.Point-pair-string")
+	// ErrIncludeCycle is returned when a file includes itself through
+	// 'include'.
+	ErrIncludeCycle = errors.New("file includes itself")
 )
 
 // Transformation parses a transform.Transformation from the io.Reader r.
@@ -35,86 +43,241 @@ var (
 // A transform.Transformation's string is a newline separated string-list where
 // string is a string-representation of a called
 // transform.Transformation-constructor. Each string is turned into its
-// respective transform.Transformations and then composed together.
+// respective transform.Transformations and then composed together. The syntax
+// is described by ParseFile.
+//
+// Arguments can also be other called transform.Transformation-constructors
+// passed to the operators 'Compose(t, ...)' which composes any number of
+// transform.Transformations, 'Inverse(t)', 'Power(t, n)' where n is an
+// integer, and 'Conjugate(a, b)' as described by their transform package
+// counterparts.
+//
+// A line like 'let name = expression' defines a name that can be used in place
+// of the geometry.Line, geometry.Point, geometry.Vector, number, or
+// transform.Transformation it's defined as on every later line. Defining a
+// name again replaces it from then on. A line like 'include "other.txt"' reads
+// the other file as if its lines were in place of the line. Included paths are
+// relative to the current directory.
 //
 // Returns an error if any string can't be parsed depending on the reason.
 // Returns ErrBadTransformation if the constructor name isn't recognized, the
 // calling syntax is bad, or the wrong number of arguments are passed to the
 // constructor. Returns a corresponding ErrBad error if an argument can't be
 // parsed to its geometry package primitive. All of these errors stem from parts
-// of the string not fitting corresponding string-representation patterns.
+// of the string not fitting corresponding string-representation patterns. Every
+// error from the string is wrapped in an *Error that says where it is. Returns
+// an *Error caused by ErrIncludeCycle if a file includes itself and by the
+// error from opening an included file if it can't be opened.
 func Transformation(r io.Reader) (transform.Transformation, error) {
-	var t transform.Transformation
-	scanner := bufio.NewScanner(r)
-	for scanner.Scan() {
-		name, args, err := split(scanner.Text())
-		if err != nil {
-			return nil, err
-		}
-		var nt transform.Transformation
-		switch name {
-		case "NoTransformation":
-			nt, err = noTransformation(args)
-		case "LineReflection":
-			nt, err = lineReflection(args)
-		case "Translation":
-			nt, err = translation(args)
-		case "Rotation":
-			nt, err = rotation(args)
-		case "GlideReflection":
-			nt, err = glideReflection(args)
-		}
-		if nt == nil {
-			return nil, ErrBadTransformation
-		}
-		if err != nil {
-			return nil, err
-		}
-		t = transform.Compose(t, nt)
+	return TransformationWithin(r, geometry.DefaultTolerance())
+}
+
+// TransformationWithin is Transformation where geometry.Numbers are compared
+// within geometry.Tolerance tol when checking if geometry.Lines and
+// transform.Transformations are degenerate.
+func TransformationWithin(
+	r io.Reader,
+	tol geometry.Tolerance,
+) (transform.Transformation, error) {
+	return transformationIn(r, "", tol)
+}
+
+// TransformationFile parses a transform.Transformation from the file at path
+// as described by Transformation except included paths are relative to the
+// file's directory.
+//
+// Returns the same errors as Transformation and the error from opening the
+// file if it can't be opened.
+func TransformationFile(path string) (transform.Transformation, error) {
+	return TransformationFileWithin(path, geometry.DefaultTolerance())
+}
+
+// TransformationFileWithin is TransformationFile where geometry.Numbers are
+// compared within geometry.Tolerance tol as described by
+// TransformationWithin.
+func TransformationFileWithin(
+	path string,
+	tol geometry.Tolerance,
+) (transform.Transformation, error) {
+	r, err := os.Open(path)
+	if err != nil {
+		return nil, err
 	}
-	if scanner.Err() != nil {
-		return nil, scanner.Err()
+	defer r.Close()
+	return transformationIn(r, path, tol)
+}
+
+// transformationIn parses a transform.Transformation from the io.Reader r
+// which is the file at path or isn't a file if path is empty.
+func transformationIn(
+	r io.Reader,
+	path string,
+	tol geometry.Tolerance,
+) (transform.Transformation, error) {
+	f, err := ParseFile(r)
+	if err != nil {
+		return nil, err
+	}
+	var t transform.Transformation
+	p := newProgram(
+		func(x Expr) error {
+			nt, err := transformation(x, tol)
+			t = transform.Compose(t, nt)
+			return err
+		},
+		check(
+			func(x Expr) error {
+				_, err := transformation(x, tol)
+				return err
+			},
+			func(x Expr) error {
+				_, err := line(x, tol)
+				return err
+			},
+			func(x Expr) error {
+				_, err := point(x)
+				return err
+			},
+			func(x Expr) error {
+				_, err := vector(x)
+				return err
+			},
+		),
+	)
+	if err := p.runFile(f, path); err != nil {
+		return nil, err
 	}
 	return t, nil
 }
 
-// split x which is a transform.Transformation's string-representation into the
-// constructor name name and arguments list.
+// transformation evaluates Expr x which is a called
+// transform.Transformation-constructor or operator.
 //
-// Returns ErrBadTransformation if x isn't formatted properly.
-func split(x string) (string, []string, error) {
-	i := strings.Index(x, "(")
-	if i == -1 || x[len(x)-1] != ')' {
-		return "", nil, ErrBadTransformation
+// Returns an *Error caused by ErrBadTransformation if x isn't a CallExpr with a
+// recognized name.
+func transformation(
+	x Expr,
+	tol geometry.Tolerance,
+) (transform.Transformation, error) {
+	if r, ok := x.(*refExpr); ok {
+		v, err := r.value(
+			evaluation{kind: "transformation", tol: tol},
+			func(x Expr) (interface{}, error) { return transformation(x, tol) },
+		)
+		t, _ := v.(transform.Transformation)
+		return t, err
+	}
+	c, ok := x.(*CallExpr)
+	if !ok {
+		return nil, notCall(x)
+	}
+	switch c.Name {
+	case "NoTransformation":
+		return noTransformation(c)
+	case "LineReflection":
+		return lineReflection(c, tol)
+	case "Translation":
+		return translation(c, tol)
+	case "Rotation":
+		return rotation(c, tol)
+	case "GlideReflection":
+		return glideReflection(c, tol)
+	case "Compose":
+		return compose(c, tol)
+	case "Inverse":
+		return inverse(c, tol)
+	case "Power":
+		return power(c, tol)
+	case "Conjugate":
+		return conjugate(c, tol)
+	}
+	return nil, unknown(c)
+}
+
+// names of every transform.Transformation-constructor and operator.
+const names = "NoTransformation, LineReflection, Translation, Rotation, " +
+	"GlideReflection, Compose, Inverse, Power, or Conjugate"
+
+// notCall returns the *Error for Expr x not being a CallExpr where one is
+// expected.
+func notCall(x Expr) error {
+	if id, ok := x.(*IdentExpr); ok {
+		return errorAt(
+			x.Pos(),
+			ErrBadTransformation,
+			fmt.Sprintf(
+				"'%s' must be followed by '(' or defined with 'let'",
+				id.Name,
+			),
+		)
+	}
+	return errorAt(x.Pos(), ErrBadTransformation, hintTransformation)
+}
+
+// unknown returns the *Error for CallExpr c not having a recognized name.
+func unknown(c *CallExpr) error {
+	return errorAt(
+		c.NamePos,
+		ErrBadTransformation,
+		fmt.Sprintf("'%s' isn't %s", c.Name, names),
+	)
+}
+
+// unstable returns an *Error at CallExpr c caused by the
+// transform.InstabilityError err from working out what c is.
+func unstable(c *CallExpr, err error) error {
+	return errorAt(
+		c.NamePos,
+		err,
+		fmt.Sprintf(
+			"the lines of '%s' are too close to being degenerate "+
+				"to work with",
+			c.Name,
+		),
+	)
+}
+
+// arity returns an *Error caused by ErrBadTransformation if CallExpr c doesn't
+// have n arguments.
+func arity(c *CallExpr, n int) error {
+	if len(c.Args) == n {
+		return nil
 	}
-	args := strings.Split(x[i+1:len(x)-1], ", ")
-	if len(args) == 1 && args[0] == "" {
-		args = nil
+	s := "s"
+	if n == 1 {
+		s = ""
 	}
-	return x[:i], args, nil
+	return errorAt(
+		c.NamePos,
+		ErrBadTransformation,
+		fmt.Sprintf("'%s' takes %d argument%s but got %d", c.Name, n, s, len(c.Args)),
+	)
 }
 
 // noTransformation parses a transform.Transformation with
-// transform.TypeNoTransformation from constructor arguments xs.
+// transform.TypeNoTransformation from constructor c.
 //
 // Returns ErrBadTransformation if any arguments are passed.
-func noTransformation(xs []string) (transform.Transformation, error) {
-	if len(xs) != 0 {
-		return nil, ErrBadTransformation
+func noTransformation(c *CallExpr) (transform.Transformation, error) {
+	if err := arity(c, 0); err != nil {
+		return nil, err
 	}
 	return transform.NoTransformation(), nil
 }
 
 // lineReflection parses a transform.Transformation with
-// transform.TypeLineReflection from constructor arguments xs.
+// transform.TypeLineReflection from constructor c.
 //
 // Returns ErrBadTransformation if there isn't exactly 1 argument passed.
 // Returns ErrBadLine if that argument can't be parsed to a geometry.Line.
-func lineReflection(xs []string) (transform.Transformation, error) {
-	if len(xs) != 1 {
-		return nil, ErrBadTransformation
+func lineReflection(
+	c *CallExpr,
+	tol geometry.Tolerance,
+) (transform.Transformation, error) {
+	if err := arity(c, 1); err != nil {
+		return nil, err
 	}
-	l, err := Line(xs[0])
+	l, err := line(c.Args[0], tol)
 	if err != nil {
 		return nil, err
 	}
@@ -122,159 +285,526 @@ func lineReflection(xs []string) (transform.Transformation, error) {
 }
 
 // translation parses a transform.Transformation with transform.TypeTranslation
-// from constructor arguments xs.
+// from constructor c.
 //
 // Returns ErrBadTransformation if there isn't exactly 1 argument passed.
 // Returns ErrBadVector if that argument can't be parsed to a geometry.Vector.
-func translation(xs []string) (transform.Transformation, error) {
-	if len(xs) != 1 {
-		return nil, ErrBadTransformation
+func translation(
+	c *CallExpr,
+	tol geometry.Tolerance,
+) (transform.Transformation, error) {
+	if err := arity(c, 1); err != nil {
+		return nil, err
 	}
-	v, err := Vector(xs[0])
+	v, err := vector(c.Args[0])
 	if err != nil {
 		return nil, err
 	}
-	return transform.Translation(v), nil
+	return transform.TranslationWithin(v, tol), nil
 }
 
 // rotation parses a transform.Transformation with transform.TypeRotation from
-// constructor arguments xs.
+// constructor c.
 //
 // Returns ErrBadTransformation if there aren't exactly 2 arguments passed.
 // Returns ErrBadPoint if the first argument can't be parsed to a
 // geometry.Point. Returns ErrBadAngle if the second argument can't be parsed
 // to a geometry.Angle.
-func rotation(xs []string) (transform.Transformation, error) {
-	if len(xs) != 2 {
-		return nil, ErrBadTransformation
+func rotation(
+	c *CallExpr,
+	tol geometry.Tolerance,
+) (transform.Transformation, error) {
+	if err := arity(c, 2); err != nil {
+		return nil, err
 	}
-	p, err := Point(xs[0])
+	p, err := point(c.Args[0])
 	if err != nil {
 		return nil, err
 	}
-	rads, err := Angle(xs[1])
+	rads, err := angle(c.Args[1])
 	if err != nil {
 		return nil, err
 	}
-	return transform.Rotation(p, rads), nil
+	return transform.RotationWithin(p, rads, tol), nil
 }
 
 // glideReflectin parses a transform.Transformation with
-// transform.TypeGlideReflection from constructor arguments xs.
+// transform.TypeGlideReflection from constructor c.
 //
 // Returns ErrBadTransformation if there aren't exactly 2 arguments passed.
 // Returns ErrBadLine if the first argument can't be parsed to a geometry.Line.
 // Returns ErrBadVector if the second argument can't be parsed to a
 // geometry.Vector.
-func glideReflection(xs []string) (transform.Transformation, error) {
-	if len(xs) != 2 {
-		return nil, ErrBadTransformation
+func glideReflection(
+	c *CallExpr,
+	tol geometry.Tolerance,
+) (transform.Transformation, error) {
+	if err := arity(c, 2); err != nil {
+		return nil, err
 	}
-	l, err := Line(xs[0])
+	l, err := line(c.Args[0], tol)
 	if err != nil {
 		return nil, err
 	}
-	v, err := Vector(xs[1])
+	v, err := vector(c.Args[1])
 	if err != nil {
 		return nil, err
 	}
-	return transform.GlideReflection(l, v), nil
+	return transform.GlideReflectionWithin(l, v, tol), nil
+}
+
+// compose parses the composition of the transform.Transformations passed to
+// operator c.
+//
+// Returns an error if any argument can't be parsed to a
+// transform.Transformation.
+func compose(
+	c *CallExpr,
+	tol geometry.Tolerance,
+) (transform.Transformation, error) {
+	ts, err := transformations(c.Args, tol)
+	if err != nil {
+		return nil, err
+	}
+	return transform.Compose(ts...), nil
+}
+
+// inverse parses the inverse of the transform.Transformation passed to
+// operator c.
+//
+// Returns ErrBadTransformation if there isn't exactly 1 argument passed or it
+// can't be parsed to a transform.Transformation. Returns an *Error caused by a
+// transform.InstabilityError if simplifying the inverse fails.
+func inverse(
+	c *CallExpr,
+	tol geometry.Tolerance,
+) (transform.Transformation, error) {
+	if err := arity(c, 1); err != nil {
+		return nil, err
+	}
+	t, err := transformation(c.Args[0], tol)
+	if err != nil {
+		return nil, err
+	}
+	inverse, err := transform.InverseWithinE(t, tol)
+	if err != nil {
+		return nil, unstable(c, err)
+	}
+	return inverse, nil
+}
+
+// power parses the power of the transform.Transformation passed to operator c.
+//
+// Returns ErrBadTransformation if there aren't exactly 2 arguments passed or
+// the first can't be parsed to a transform.Transformation. Returns
+// ErrBadNumber if the second isn't an integer. Returns an *Error caused by a
+// transform.InstabilityError if simplifying the power fails.
+func power(
+	c *CallExpr,
+	tol geometry.Tolerance,
+) (transform.Transformation, error) {
+	if err := arity(c, 2); err != nil {
+		return nil, err
+	}
+	t, err := transformation(c.Args[0], tol)
+	if err != nil {
+		return nil, err
+	}
+	n, err := integer(c.Args[1])
+	if err != nil {
+		return nil, err
+	}
+	p, err := transform.PowerWithinE(t, n, tol)
+	if err != nil {
+		return nil, unstable(c, err)
+	}
+	return p, nil
+}
+
+// conjugate parses the conjugate of the first transform.Transformation passed
+// to operator c by the second.
+//
+// Returns ErrBadTransformation if there aren't exactly 2 arguments passed or
+// they can't be parsed to transform.Transformations. Returns an *Error caused
+// by a transform.InstabilityError if simplifying the inverse of the second
+// fails.
+func conjugate(
+	c *CallExpr,
+	tol geometry.Tolerance,
+) (transform.Transformation, error) {
+	if err := arity(c, 2); err != nil {
+		return nil, err
+	}
+	ts, err := transformations(c.Args, tol)
+	if err != nil {
+		return nil, err
+	}
+	conjugate, err := transform.ConjugateWithinE(ts[0], ts[1], tol)
+	if err != nil {
+		return nil, unstable(c, err)
+	}
+	return conjugate, nil
+}
+
+// transformations parses each of Exprs xs to a transform.Transformation.
+func transformations(
+	xs []Expr,
+	tol geometry.Tolerance,
+) ([]transform.Transformation, error) {
+	ts := make([]transform.Transformation, len(xs))
+	for i, x := range xs {
+		t, err := transformation(x, tol)
+		if err != nil {
+			return nil, err
+		}
+		ts[i] = t
+	}
+	return ts, nil
 }
 
 // Line parses a geometry.Line from the string x.
 //
-// Returns ErrBadLine if the string doesn't fit the geometry.Line
-// string-representation pattern.
+// Returns an *Error caused by ErrBadLine if the string doesn't fit the
+// geometry.Line string-representation pattern.
 func Line(x string) (geometry.Line, error) {
-	if x[0] != '{' || x[len(x)-1] != '}' {
-		return geometry.Line{}, ErrBadLine
+	return LineWithin(x, geometry.DefaultTolerance())
+}
+
+// LineWithin is Line where the geometry.Points are compared within
+// geometry.Tolerance tol.
+func LineWithin(x string, tol geometry.Tolerance) (geometry.Line, error) {
+	e, err := parseExpr(x)
+	if err != nil {
+		err = recause(err, start, ErrBadLine, hintLine)
+		return geometry.Line{}, withSnippet(err, x)
 	}
-	x = x[1 : len(x)-1]
-	i := strings.Index(x, ")")
-	if i == -1 {
-		return geometry.Line{}, ErrBadLine
+	l, err := line(e, tol)
+	return l, withSnippet(err, x)
+}
+
+// line evaluates Expr x to a geometry.Line.
+//
+// Returns an *Error caused by ErrBadLine if x isn't a LineExpr of two
+// geometry.Points and by geometry.ErrNoLine if the geometry.Points are the
+// same.
+func line(x Expr, tol geometry.Tolerance) (geometry.Line, error) {
+	if r, ok := x.(*refExpr); ok {
+		v, err := r.value(
+			evaluation{kind: "line", tol: tol},
+			func(x Expr) (interface{}, error) { return line(x, tol) },
+		)
+		l, _ := v.(geometry.Line)
+		return l, err
 	}
-	if x[i+1] != ' ' {
-		return geometry.Line{}, ErrBadLine
+	l, ok := x.(*LineExpr)
+	if !ok {
+		return geometry.Line{}, mismatch(x, ErrBadLine, hintLine)
+	}
+	a, err := point(l.A)
+	if err != nil {
+		return geometry.Line{}, recause(err, l.Pos(), ErrBadLine, hintLine)
 	}
-	sa, sb := x[:i+1], x[i+2:]
-	a, err := Point(sa)
+	b, err := point(l.B)
 	if err != nil {
-		return geometry.Line{}, ErrBadLine
+		return geometry.Line{}, recause(err, l.Pos(), ErrBadLine, hintLine)
 	}
-	b, err := Point(sb)
+	gl, err := tol.NewLineFromPoints(a, b)
 	if err != nil {
-		return geometry.Line{}, ErrBadLine
+		return geometry.Line{}, errorAt(l.Pos(), err, hintNoLine)
 	}
-	return geometry.NewLineFromPoints(a, b)
+	return gl, nil
 }
 
 // Vector parses a geometry.Vector from the string x.
 //
-// Returns ErrBadVector if the string doesn't fit the geometry.Vector
-// string-representation pattern.
-func Vector(sx string) (geometry.Vector, error) {
-	if sx[0] != '<' || sx[len(sx)-1] != '>' {
-		return geometry.Vector{}, ErrBadVector
+// Returns an *Error caused by ErrBadVector if the string doesn't fit the
+// geometry.Vector string-representation pattern.
+func Vector(x string) (geometry.Vector, error) {
+	e, err := parseExpr(x)
+	if err != nil {
+		err = recause(err, start, ErrBadVector, hintVector)
+		return geometry.Vector{}, withSnippet(err, x)
+	}
+	v, err := vector(e)
+	return v, withSnippet(err, x)
+}
+
+// vector evaluates Expr x to a geometry.Vector.
+//
+// Returns an *Error caused by ErrBadVector if x isn't a VectorExpr of two
+// geometry.Numbers.
+func vector(x Expr) (geometry.Vector, error) {
+	if r, ok := x.(*refExpr); ok {
+		v, err := r.value(
+			evaluation{kind: "vector"},
+			func(x Expr) (interface{}, error) { return vector(x) },
+		)
+		vec, _ := v.(geometry.Vector)
+		return vec, err
 	}
-	fs := strings.Split(sx[1:len(sx)-1], " ")
-	if len(fs) != 2 {
-		return geometry.Vector{}, ErrBadVector
+	v, ok := x.(*VectorExpr)
+	if !ok {
+		return geometry.Vector{}, mismatch(x, ErrBadVector, hintVector)
 	}
-	i, err := Number(fs[0])
+	i, err := number(v.I)
 	if err != nil {
-		return geometry.Vector{}, ErrBadVector
+		return geometry.Vector{}, recause(err, v.Pos(), ErrBadVector, hintVector)
 	}
-	j, err := Number(fs[1])
+	j, err := number(v.J)
 	if err != nil {
-		return geometry.Vector{}, ErrBadVector
+		return geometry.Vector{}, recause(err, v.Pos(), ErrBadVector, hintVector)
 	}
 	return geometry.Vector{I: i, J: j}, nil
 }
 
 // Point parses a geometry.Point from the string x.
 //
-// Returns ErrBadPoint if the string doesn't fit the geometry.Point
-// string-representation pattern.
+// Returns an *Error caused by ErrBadPoint if the string doesn't fit the
+// geometry.Point string-representation pattern.
 func Point(x string) (geometry.Point, error) {
-	if x[0] != '(' || x[len(x)-1] != ')' {
-		return geometry.Point{}, ErrBadPoint
+	e, err := parseExpr(x)
+	if err != nil {
+		err = recause(err, start, ErrBadPoint, hintPoint)
+		return geometry.Point{}, withSnippet(err, x)
+	}
+	p, err := point(e)
+	return p, withSnippet(err, x)
+}
+
+// PointPair parses 2 geometry.Points from the string x which looks like
+// '(x y) (x y)'.
+//
+// The geometry.Points are separated by whitespace and each is a
+// geometry.Point as described by Point.
+//
+// Returns an *Error caused by ErrBadPointPair if x isn't 2 expressions and the
+// *Error from Point if either geometry.Point is bad.
+func PointPair(x string) (geometry.Point, geometry.Point, error) {
+	p := &parser{ts: tokenize(x)}
+	var ps [2]geometry.Point
+	for i := range ps {
+		t := p.peek()
+		if t.kind == tokenEOF || i > 0 && !t.spaceBefore {
+			err := errorAt(t.pos, ErrBadPointPair, hintPointPair)
+			return geometry.Point{}, geometry.Point{}, withSnippet(err, x)
+		}
+		e, err := p.expr()
+		if err != nil {
+			err = recause(err, t.pos, ErrBadPoint, hintPoint)
+			return geometry.Point{}, geometry.Point{}, withSnippet(err, x)
+		}
+		if ps[i], err = point(e); err != nil {
+			return geometry.Point{}, geometry.Point{}, withSnippet(err, x)
+		}
 	}
-	fs := strings.Split(x[1:len(x)-1], " ")
-	if len(fs) != 2 {
-		return geometry.Point{}, ErrBadPoint
+	if t := p.peek(); t.kind != tokenEOF {
+		err := errorAt(t.pos, ErrBadPointPair, hintPointPair)
+		return geometry.Point{}, geometry.Point{}, withSnippet(err, x)
 	}
-	nx, err := Number(fs[0])
+	return ps[0], ps[1], nil
+}
+
+// point evaluates Expr x to a geometry.Point.
+//
+// Returns an *Error caused by ErrBadPoint if x isn't a PointExpr of two
+// geometry.Numbers.
+func point(x Expr) (geometry.Point, error) {
+	if r, ok := x.(*refExpr); ok {
+		v, err := r.value(
+			evaluation{kind: "point"},
+			func(x Expr) (interface{}, error) { return point(x) },
+		)
+		p, _ := v.(geometry.Point)
+		return p, err
+	}
+	p, ok := x.(*PointExpr)
+	if !ok {
+		return geometry.Point{}, mismatch(x, ErrBadPoint, hintPoint)
+	}
+	nx, err := number(p.X)
 	if err != nil {
-		return geometry.Point{}, ErrBadPoint
+		return geometry.Point{}, recause(err, p.Pos(), ErrBadPoint, hintPoint)
 	}
-	ny, err := Number(fs[1])
+	ny, err := number(p.Y)
 	if err != nil {
-		return geometry.Point{}, ErrBadPoint
+		return geometry.Point{}, recause(err, p.Pos(), ErrBadPoint, hintPoint)
 	}
 	return geometry.Point{X: nx, Y: ny}, nil
 }
 
 // Number parses a geometry.Number from the string x.
 //
-// Returns ErrBadNumber if the string doesn't fit the geometry.Number
-// string-representation pattern.
+// The string is a number as described by ParseFile like '-1.5', 'pi/3', or
+// '90deg'.
+//
+// Returns an *Error caused by ErrBadNumber if the string isn't a finite
+// number.
 func Number(x string) (geometry.Number, error) {
+	if n, ok := decimal(x); ok {
+		return n, nil
+	}
+	e, err := parseExpr(x)
+	if err != nil {
+		err = recause(err, start, ErrBadNumber, hintNumber)
+		return 0, withSnippet(err, x)
+	}
+	n, err := number(e)
+	return n, withSnippet(err, x)
+}
+
+// decimal returns the geometry.Number the string x is if it's a lone decimal
+// like '-1.5e-3' so plain numbers are read without being parsed.
+//
+// Returns false if x is anything else, including numbers the grammar doesn't
+// have like 'NaN', 'Inf', and '0x1p3', or if x is too big to be finite.
+func decimal(x string) (geometry.Number, bool) {
+	digits := strings.TrimPrefix(x, "-")
+	if digits == "" || !isNumberStart(digits) ||
+		numberLength(digits) != len(digits) {
+		return 0, false
+	}
 	n, err := strconv.ParseFloat(x, 64)
 	if err != nil {
-		return 0, ErrBadNumber
+		return 0, false
+	}
+	return geometry.Number(n), true
+}
+
+// number evaluates Expr x to a geometry.Number.
+//
+// Returns an *Error caused by ErrBadNumber if x isn't a number, uses a constant
+// that isn't one of the constants, divides by 0, or isn't finite.
+func number(x Expr) (geometry.Number, error) {
+	switch x := x.(type) {
+	case *NumberExpr:
+		v, err := strconv.ParseFloat(x.Value, 64)
+		if err != nil {
+			return 0, errorAt(x.Pos(), ErrBadNumber, hintNumber)
+		}
+		if x.Unit == "" {
+			return geometry.Number(v), nil
+		}
+		u, ok := constants[x.Unit]
+		if !ok {
+			p := after(x.Pos(), len(x.Value))
+			return 0, problemAt(p, ErrBadNumber, hintConstant)
+		}
+		return finite(geometry.Number(v*u), x.Pos())
+	case *refExpr:
+		v, err := x.value(
+			evaluation{kind: "number"},
+			func(x Expr) (interface{}, error) { return number(x) },
+		)
+		n, _ := v.(geometry.Number)
+		return n, err
+	case *IdentExpr:
+		v, ok := constants[x.Name]
+		if !ok {
+			return 0, problemAt(x.Pos(), ErrBadNumber, undefined(x.Name))
+		}
+		return geometry.Number(v), nil
+	case *ParenExpr:
+		return number(x.X)
+	case *UnaryExpr:
+		v, err := number(x.X)
+		if x.Op == "-" {
+			v = -v
+		}
+		return v, err
+	case *BinaryExpr:
+		return binary(x)
+	}
+	return 0, errorAt(x.Pos(), ErrBadNumber, hintNumber)
+}
+
+// binary evaluates BinaryExpr x to a geometry.Number.
+//
+// Returns an *Error caused by ErrBadNumber if either side isn't a number, x
+// divides by 0, or the result isn't finite.
+func binary(x *BinaryExpr) (geometry.Number, error) {
+	a, err := number(x.X)
+	if err != nil {
+		return 0, err
+	}
+	b, err := number(x.Y)
+	if err != nil {
+		return 0, err
 	}
-	return geometry.Number(n), nil
+	var n geometry.Number
+	switch x.Op {
+	case "+":
+		n = a + b
+	case "-":
+		n = a - b
+	case "*":
+		n = a * b
+	default:
+		if b == 0 {
+			return 0, problemAt(x.OpPos, ErrBadNumber, hintDivide)
+		}
+		n = a / b
+	}
+	return finite(n, x.OpPos)
+}
+
+// finite returns geometry.Number n if it's finite and an *Error at Position p
+// caused by ErrBadNumber otherwise.
+func finite(n geometry.Number, p Position) (geometry.Number, error) {
+	if math.IsInf(float64(n), 0) || math.IsNaN(float64(n)) {
+		return 0, problemAt(p, ErrBadNumber, hintFinite)
+	}
+	return n, nil
+}
+
+// constants that numbers can use by name or as a unit like '90deg'.
+var constants = map[string]float64{
+	"pi":  math.Pi,
+	"tau": 2 * math.Pi,
+	"deg": math.Pi / 180,
+}
+
+// integer evaluates Expr x to an int.
+//
+// Returns an *Error caused by ErrBadNumber if x isn't a whole
+// geometry.Number.
+func integer(x Expr) (int, error) {
+	n, err := number(x)
+	if err != nil {
+		return 0, err
+	}
+	if n != geometry.Number(math.Trunc(float64(n))) ||
+		math.Abs(float64(n)) > math.MaxInt32 {
+		return 0, errorAt(x.Pos(), ErrBadNumber, hintInteger)
+	}
+	return int(n), nil
 }
 
-// Angle parses a geometry.Angle from the string x.
+// Angle parses a geometry.Angle in radians from the string x.
 //
-// Returns ErrBadAngle if the string doesn't fit the geometry.Angle
-// string-representation pattern.
+// The string is a number as described by ParseFile so angles can be written
+// like 'pi/2' or '90deg'.
+//
+// Returns an *Error caused by ErrBadAngle if the string isn't a finite number.
 func Angle(x string) (geometry.Angle, error) {
-	a, err := Number(x)
+	if a, ok := decimal(x); ok {
+		return geometry.Angle(a), nil
+	}
+	e, err := parseExpr(x)
+	if err != nil {
+		err = recause(err, start, ErrBadAngle, hintAngle)
+		return 0, withSnippet(err, x)
+	}
+	a, err := angle(e)
+	return a, withSnippet(err, x)
+}
+
+// angle evaluates Expr x to a geometry.Angle in radians.
+//
+// Returns an *Error caused by ErrBadAngle if x isn't a number.
+func angle(x Expr) (geometry.Angle, error) {
+	a, err := number(x)
 	if err != nil {
-		return 0, ErrBadAngle
+		return 0, recause(err, x.Pos(), ErrBadAngle, hintAngle)
 	}
 	return geometry.Angle(a), nil
 }
@@ -0,0 +1,55 @@
+package parse
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jwowillo/viztransform/geometry"
+)
+
+// TestPointPair checks point-pairs are parsed by the tokenizer with errors at
+// the character they start at.
+func TestPointPair(t *testing.T) {
+	cases := []struct {
+		x            string
+		a, b         geometry.Point
+		err          error
+		line, column int
+	}{
+		{x: "(0 0) (1 2)", b: geometry.Point{X: 1, Y: 2}},
+		{x: "  (1 -2)\t((1+2) 0)  ", a: geometry.Point{X: 1, Y: -2},
+			b: geometry.Point{X: 3, Y: 0}},
+		{x: "(0 0)", err: ErrBadPointPair, line: 1, column: 6},
+		{x: "(0 0)(1 2)", err: ErrBadPointPair, line: 1, column: 6},
+		{x: "(0 0) (1 2) (3 4)", err: ErrBadPointPair, line: 1, column: 13},
+		{x: "  (0 0) (x 2)", err: ErrBadPoint, line: 1, column: 10},
+		{x: "(é 0) (1 2)", err: ErrBadPoint, line: 1, column: 2},
+		{x: "(0 0) (é 2", err: ErrBadPoint, line: 1, column: 11},
+		{x: "", err: ErrBadPointPair, line: 1, column: 1},
+	}
+	for _, c := range cases {
+		a, b, err := PointPair(c.x)
+		if c.err == nil {
+			if err != nil || a != c.a || b != c.b {
+				t.Errorf("%q gives %v, %v, and %v but should give %v and %v",
+					c.x, a, b, err, c.a, c.b)
+			}
+			continue
+		}
+		checkAt(t, c.x, err, c.err, c.line, c.column)
+	}
+}
+
+// checkAt fails the test if err from parsing x isn't an *Error at line and
+// column caused by cause.
+func checkAt(t *testing.T, x string, err, cause error, line, column int) {
+	t.Helper()
+	var e *Error
+	if !errors.As(err, &e) {
+		t.Fatalf("%q gives %v but should give an *Error", x, err)
+	}
+	if !errors.Is(err, cause) || e.Line != line || e.Column != column {
+		t.Errorf("%q gives %v at %d:%d but should give %v at %d:%d",
+			x, err, e.Line, e.Column, cause, line, column)
+	}
+}
@@ -0,0 +1,406 @@
+package parse
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ParseFile parses the syntax of a transform.Transformation's string from the
+// io.Reader r without turning it into a transform.Transformation.
+//
+// Each line holds at most one top-level expression. Blank lines are skipped
+// and '#' starts a comment that runs to the end of the line. Spaces and tabs
+// can go anywhere between tokens and newlines can also go anywhere inside
+// brackets so long expressions can be split over lines. An expression is a
+// called transform.Transformation-constructor or operator like 'Name(a, b)',
+// a geometry.Line like '{a b}', a geometry.Point like '(a b)', a
+// geometry.Vector like '<a b>', or a number.
+//
+// A line can instead define a name for an expression like 'let name = (0 0)'
+// or include another file like 'include "other.txt"'.
+//
+// Numbers can be arithmetic with '+', '-', '*', '/', and parentheses on
+// numbers like '1.5e3', constants like 'pi', and numbers followed by a
+// constant they're multiplied by like '90deg'. A '+' or '-' with whitespace
+// before it and none after it is a sign so '(1 -2)' is a geometry.Point while
+// '(1 - 2)' is a number.
+//
+// Returns an *Error caused by ErrBadTransformation if the syntax is bad outside
+// of a geometry.Line, geometry.Point, or geometry.Vector and by the
+// corresponding ErrBad error if it's bad inside of one.
+func ParseFile(r io.Reader) (*File, error) {
+	bs, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	f := &File{src: string(bs)}
+	if err := f.parse(); err != nil {
+		return nil, withSnippet(err, f.src)
+	}
+	return f, nil
+}
+
+// parse the File's source into its Exprs and Comments.
+func (f *File) parse() error {
+	p := &parser{ts: tokenize(f.src)}
+	for {
+		switch p.peek().kind {
+		case tokenEOF:
+			f.Comments = p.comments
+			return nil
+		case tokenNewline:
+			p.next()
+			continue
+		}
+		x, err := p.statement()
+		if err != nil {
+			return err
+		}
+		if t := p.peek(); t.kind != tokenNewline && t.kind != tokenEOF {
+			hint := "each line holds one transformation"
+			if id, ok := x.(*IdentExpr); ok && t.text == "(" {
+				hint = fmt.Sprintf(
+					"'%s' must be followed by '(' without a space",
+					id.Name,
+				)
+			}
+			return errorAt(t.pos, ErrBadTransformation, hint)
+		}
+		f.Exprs = append(f.Exprs, x)
+	}
+}
+
+// parseExpr parses the string x which must hold exactly one expression.
+//
+// Returns an *Error caused by ErrBadTransformation if it doesn't.
+func parseExpr(x string) (Expr, error) {
+	p := &parser{ts: tokenize(x)}
+	e, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, errorAt(t.pos, ErrBadTransformation, "expected the end")
+	}
+	return e, nil
+}
+
+// parser of a list of tokens.
+type parser struct {
+	ts []token
+	// i is the index of the next token.
+	i int
+	// depth is the number of brackets the next token is inside of.
+	depth int
+	// comments skipped so far.
+	comments []Comment
+}
+
+// peek returns the next token without consuming it.
+//
+// Comments are skipped and collected along the way and so are newlines inside
+// of brackets.
+func (p *parser) peek() token {
+	for {
+		t := p.ts[p.i]
+		switch {
+		case t.kind == tokenComment:
+			p.comments = append(p.comments, Comment{Pos: t.pos, Text: t.text})
+		case t.kind == tokenNewline && p.depth > 0:
+		default:
+			return t
+		}
+		p.i++
+	}
+}
+
+// next consumes and returns the next token as described by peek.
+func (p *parser) next() token {
+	t := p.peek()
+	if t.kind != tokenEOF {
+		p.i++
+	}
+	return t
+}
+
+// statement parses the next top-level expression which can also be a LetExpr
+// or an IncludeExpr.
+func (p *parser) statement() (Expr, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokenIdent && t.text == "let":
+		p.next()
+		return p.let(t)
+	case t.kind == tokenIdent && t.text == "include":
+		p.next()
+		return p.include(t)
+	}
+	return p.expr()
+}
+
+// let parses the LetExpr that starts with the 'let' in token let.
+//
+// Returns an *Error caused by ErrBadTransformation if 'let' isn't followed by a
+// name, '=', and an expression.
+func (p *parser) let(let token) (Expr, error) {
+	name := p.next()
+	if name.kind != tokenIdent {
+		return nil, errorAt(name.pos, ErrBadTransformation, hintLet)
+	}
+	if t := p.next(); t.kind != tokenAssign {
+		return nil, errorAt(t.pos, ErrBadTransformation, hintLet)
+	}
+	x, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	return &LetExpr{
+		Let:     let.pos,
+		Name:    name.text,
+		NamePos: name.pos,
+		Value:   x,
+	}, nil
+}
+
+// include parses the IncludeExpr that starts with the 'include' in token
+// include.
+//
+// Returns an *Error caused by ErrBadTransformation if 'include' isn't followed
+// by a double-quoted path.
+func (p *parser) include(include token) (Expr, error) {
+	t := p.next()
+	if t.kind != tokenString {
+		return nil, errorAt(t.pos, ErrBadTransformation, hintInclude)
+	}
+	path, err := strconv.Unquote(t.text)
+	if err != nil {
+		return nil, errorAt(t.pos, ErrBadTransformation, hintInclude)
+	}
+	return &IncludeExpr{
+		Include: include.pos,
+		PathPos: t.pos,
+		Path:    path,
+		Value:   t.text,
+	}, nil
+}
+
+// expr parses the next expression.
+//
+// '*' and '/' are done before '+' and '-' and each is done left to right. A
+// '+' or '-' with whitespace before it and none after it is the sign of the
+// next expression instead so '(1 -2)' is a PointExpr and '(1 - 2)' and '(1-2)'
+// are ParenExprs.
+func (p *parser) expr() (Expr, error) {
+	x, err := p.product()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); isOperator(t, "+", "-") && p.isBinary(); t = p.peek() {
+		p.next()
+		y, err := p.product()
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryExpr{X: x, OpPos: t.pos, Op: t.text, Y: y}
+	}
+	return x, nil
+}
+
+// product parses the next expression made of signed expressions multiplied or
+// divided together.
+func (p *parser) product() (Expr, error) {
+	x, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); isOperator(t, "*", "/"); t = p.peek() {
+		p.next()
+		y, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		x = &BinaryExpr{X: x, OpPos: t.pos, Op: t.text, Y: y}
+	}
+	return x, nil
+}
+
+// unary parses the next expression which can be signed.
+func (p *parser) unary() (Expr, error) {
+	t := p.peek()
+	if !isOperator(t, "+", "-") {
+		return p.primary()
+	}
+	p.next()
+	x, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	return &UnaryExpr{OpPos: t.pos, Op: t.text, X: x}, nil
+}
+
+// primary parses the next expression without any operators outside of
+// brackets.
+//
+// A name followed by '(' with no whitespace between is a CallExpr and is an
+// IdentExpr otherwise so names can be the parts of geometry.Lines like
+// '{a (1 1)}'. A name right after a number with no whitespace between is the
+// number's unit.
+func (p *parser) primary() (Expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNumber:
+		x := &NumberExpr{ValuePos: t.pos, Value: t.text}
+		if u := p.ts[p.i]; u.kind == tokenIdent && !u.spaceBefore {
+			p.i++
+			x.Unit = u.text
+		}
+		return x, nil
+	case tokenIdent:
+		u := p.ts[p.i]
+		if u.kind == tokenOpen && u.text == "(" && !u.spaceBefore {
+			return p.call(t)
+		}
+		return &IdentExpr{NamePos: t.pos, Name: t.text}, nil
+	case tokenOpen:
+		return p.pair(t)
+	}
+	return nil, errorAt(t.pos, ErrBadTransformation, unexpected(t))
+}
+
+// isBinary returns true if the next token, which is a '+' or '-', goes between
+// two expressions instead of being the sign of the next one.
+func (p *parser) isBinary() bool {
+	if !p.ts[p.i].spaceBefore {
+		return true
+	}
+	switch u := p.ts[p.i+1]; u.kind {
+	case tokenNewline, tokenComment, tokenEOF:
+		return true
+	default:
+		return u.spaceBefore
+	}
+}
+
+// isOperator returns true if token t is one of the operators ops.
+func isOperator(t token, ops ...string) bool {
+	if t.kind != tokenOperator {
+		return false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+// call parses the arguments of the CallExpr with the name in token name which
+// is followed by '('.
+//
+// Returns an *Error caused by ErrBadTransformation if the arguments aren't a
+// comma-separated list between parentheses. A ',' can also follow the last
+// argument so arguments split over lines can all end with one.
+func (p *parser) call(name token) (Expr, error) {
+	p.next()
+	p.depth++
+	x := &CallExpr{Name: name.text, NamePos: name.pos}
+	if t := p.peek(); t.kind == tokenClose && t.text == ")" {
+		p.next()
+		x.Rparen = t.pos
+		p.depth--
+		return x, nil
+	}
+	for {
+		arg, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		x.Args = append(x.Args, arg)
+		t := p.next()
+		if t.kind == tokenComma {
+			if t = p.peek(); t.kind != tokenClose || t.text != ")" {
+				continue
+			}
+			p.next()
+		}
+		if t.kind != tokenClose || t.text != ")" {
+			return nil, errorAt(
+				t.pos,
+				ErrBadTransformation,
+				"arguments are separated by ',' and end with ')'",
+			)
+		}
+		x.Rparen = t.pos
+		p.depth--
+		return x, nil
+	}
+}
+
+// pair parses the LineExpr, PointExpr, or VectorExpr that starts with the
+// bracket in token open or the ParenExpr if the bracket is a '(' around a
+// single expression.
+//
+// Returns an *Error caused by the ErrBad error for the expression if the
+// expression doesn't have exactly two parts or the brackets don't match.
+func (p *parser) pair(open token) (Expr, error) {
+	close := closers[open.text]
+	bad, hint := pairErrors[open.text], pairHints[open.text]
+	p.depth++
+	a, err := p.expr()
+	if err != nil {
+		return nil, recause(err, open.pos, bad, hint)
+	}
+	if t := p.peek(); open.text == "(" && t.kind == tokenClose && t.text == ")" {
+		p.next()
+		p.depth--
+		return &ParenExpr{Lparen: open.pos, X: a, Rparen: t.pos}, nil
+	}
+	b, err := p.expr()
+	if err != nil {
+		return nil, recause(err, open.pos, bad, hint)
+	}
+	t := p.next()
+	if t.kind != tokenClose || t.text != close {
+		return nil, errorAt(t.pos, bad, hint)
+	}
+	p.depth--
+	switch open.text {
+	case "{":
+		return &LineExpr{Lbrace: open.pos, A: a, B: b, Rbrace: t.pos}, nil
+	case "<":
+		return &VectorExpr{Langle: open.pos, I: a, J: b, Rangle: t.pos}, nil
+	}
+	return &PointExpr{Lparen: open.pos, X: a, Y: b, Rparen: t.pos}, nil
+}
+
+var (
+	// closers are the closing brackets for each opening bracket.
+	closers = map[string]string{"(": ")", "<": ">", "{": "}"}
+	// pairErrors are the errors for bad expressions started by each opening
+	// bracket.
+	pairErrors = map[string]error{
+		"(": ErrBadPoint,
+		"<": ErrBadVector,
+		"{": ErrBadLine,
+	}
+	// pairHints are the hints for bad expressions started by each opening
+	// bracket.
+	pairHints = map[string]string{
+		"(": hintPoint,
+		"<": hintVector,
+		"{": hintLine,
+	}
+)
+
+// unexpected returns the hint for token t being somewhere an expression was
+// expected.
+func unexpected(t token) string {
+	switch t.kind {
+	case tokenEOF:
+		return "expected more before the end"
+	case tokenNewline:
+		return "expected more before the end of the line"
+	}
+	return fmt.Sprintf("'%s' can't start an expression", t.text)
+}
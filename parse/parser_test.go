@@ -0,0 +1,183 @@
+package parse
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/jwowillo/viztransform/geometry"
+	"github.com/jwowillo/viztransform/transform"
+)
+
+// TestNumberOperators checks numbers follow the usual precedence with each
+// operator done left to right.
+func TestNumberOperators(t *testing.T) {
+	cases := []struct {
+		x    string
+		want geometry.Number
+	}{
+		{"1+2*3", 7},
+		{"(1+2)*3", 9},
+		{"2*3-4/2", 4},
+		{"8/2/2", 2},
+		{"1-2-3", -4},
+		{"2-(3-4)", 3},
+		{"-2*3", -6},
+		{"-(2+3)", -5},
+		{"1 - -2", 3},
+		{"+1", 1},
+		{"- -1", 1},
+		{" 1 + 2 ", 3},
+		{"1.5e2", 150},
+		{"2pi", 2 * math.Pi},
+		{"pi/3", math.Pi / 3},
+		{"tau/4", math.Pi / 2},
+		{"90deg", math.Pi / 2},
+		{"1/2*90deg", math.Pi / 4},
+		{"-180deg", -math.Pi},
+	}
+	for _, c := range cases {
+		got, err := Number(c.x)
+		if err != nil {
+			t.Errorf("%q gives %v", c.x, err)
+			continue
+		}
+		if !geometry.AreEqual(got, c.want) {
+			t.Errorf("%q is %v but should be %v", c.x, got, c.want)
+		}
+	}
+}
+
+// TestSigns checks a '+' or '-' with a space before and not after it is a sign
+// so it starts the next part of a geometry.Point or geometry.Vector.
+func TestSigns(t *testing.T) {
+	cases := []struct {
+		x    string
+		want geometry.Point
+	}{
+		{"(1 -2)", geometry.Point{X: 1, Y: -2}},
+		{"(1 +2)", geometry.Point{X: 1, Y: 2}},
+		{"(1 - 2 3)", geometry.Point{X: -1, Y: 3}},
+		{"(1-2 3)", geometry.Point{X: -1, Y: 3}},
+		{"(1- -2 -3)", geometry.Point{X: 3, Y: -3}},
+		{"(-1 -2)", geometry.Point{X: -1, Y: -2}},
+		{"((1) (2))", geometry.Point{X: 1, Y: 2}},
+		{"( 1 2 )", geometry.Point{X: 1, Y: 2}},
+	}
+	for _, c := range cases {
+		got, err := Point(c.x)
+		if err != nil {
+			t.Errorf("%q gives %v", c.x, err)
+			continue
+		}
+		if !geometry.AreSamePoint(got, c.want) {
+			t.Errorf("%q is %v but should be %v", c.x, got, c.want)
+		}
+	}
+	v, err := Vector("<1 -2>")
+	if err != nil || v != (geometry.Vector{I: 1, J: -2}) {
+		t.Errorf("%q is %v and %v but should be %v", "<1 -2>", v, err,
+			geometry.Vector{I: 1, J: -2})
+	}
+}
+
+// TestComments checks comments and blank lines are skipped wherever they
+// are.
+func TestComments(t *testing.T) {
+	cases := []string{
+		"# first\nTranslation(<1 2>) # after\n\n# last",
+		"Translation(<1 2>)#no space",
+		"Compose( # open\n\t# before\n\tTranslation(<1 0>),\n\n\t" +
+			"Translation(<0 2>), # trailing\n)\n",
+		"\n\n   Translation(<1 2>)   \n\n",
+		"Translation(<1 2>) # let a = 3\n# include \"missing.txt\"",
+	}
+	want := testTranslation(1, 2)
+	for _, x := range cases {
+		got, err := Transformation(strings.NewReader(x))
+		if err != nil {
+			t.Errorf("%q gives %v", x, err)
+			continue
+		}
+		checkMatrix(t, x, want, got)
+	}
+}
+
+// TestTransformationOperators checks the operators and lines of a string are
+// composed like their transform package counterparts.
+func TestTransformationOperators(t *testing.T) {
+	r := testRotation(1, 0, 90)
+	v := testTranslation(2, 0)
+	cases := []struct {
+		x    string
+		want transform.Transformation
+	}{
+		{"", transform.NoTransformation()},
+		{"NoTransformation()", transform.NoTransformation()},
+		{"Translation(<2 0>)\nRotation((1 0), pi/2)", transform.Compose(v, r)},
+		{"Compose(Translation(<2 0>), Rotation((1 0), pi/2))",
+			transform.Compose(v, r)},
+		{"Compose(Translation(<2 0>),)", v},
+		{"Compose(\n\tTranslation(<2 0>),\n\tRotation((1 0), pi/2),\n)",
+			transform.Compose(v, r)},
+		{"Compose()", transform.NoTransformation()},
+		{"Inverse(Rotation((1 0), pi/2))", transform.Inverse(r)},
+		{"Power(Rotation((1 0), pi/2), 3)", transform.Power(r, 3)},
+		{"Power(Rotation((1 0), pi/2), -1)", transform.Inverse(r)},
+		{"Power(Translation(<2 0>), 0)", transform.NoTransformation()},
+		{"Conjugate(Translation(<2 0>), Rotation((1 0), pi/2))",
+			transform.Conjugate(v, r)},
+		{"GlideReflection({(0 0) (1 0)}, <2 0>)", transform.Compose(
+			testReflection(0, 0, 1, 0),
+			v,
+		)},
+		{"let r = Rotation((1 0), pi/2)\nCompose(r, r)", transform.Power(r, 2)},
+		{"let a = 1\nlet a = a + 1\nTranslation(<a 0>)", v},
+	}
+	for _, c := range cases {
+		got, err := Transformation(strings.NewReader(c.x))
+		if err != nil {
+			t.Errorf("%q gives %v", c.x, err)
+			continue
+		}
+		checkMatrix(t, c.x, c.want, got)
+	}
+}
+
+// TestTransformationUnstable checks operators whose results can't be
+// simplified give an *Error caused by transform.ErrNumericalInstability at the
+// operator instead of panicking.
+func TestTransformationUnstable(t *testing.T) {
+	a := "LineReflection({(0 2) (-0.3039722258743317 2.952680894054774)})"
+	b := "LineReflection({(4 0) (3.6960286315385957 0.9526811676293918)})"
+	c := "LineReflection({(2 0) (1.696028726616178 0.9526811979657297)})"
+	for _, x := range []string{
+		"Power(Compose(" + a + ", " + b + ", " + c + "), 2)",
+		"Inverse(Compose(" + c + ", " + b + ", " + a + "))",
+		"Conjugate(NoTransformation(), Compose(" + c + ", " + b + ", " + a +
+			"))",
+	} {
+		_, err := Transformation(strings.NewReader(x))
+		checkAt(t, x, err, transform.ErrNumericalInstability, 1, 1)
+	}
+}
+
+// TestTransformationWithin checks every constructor treats parts within the
+// geometry.Tolerance of 0 as 0.
+func TestTransformationWithin(t *testing.T) {
+	tol := geometry.Tolerance{Absolute: 0.01}
+	for _, x := range []string{
+		"Translation(<0.001 0>)",
+		"Rotation((1 2), 0.001)",
+	} {
+		got, err := TransformationWithin(strings.NewReader(x), tol)
+		if err != nil {
+			t.Errorf("%q gives %v", x, err)
+			continue
+		}
+		if len(got) != 0 {
+			t.Errorf("%q gives %v but should give %v within %v",
+				x, got, transform.NoTransformation(), tol)
+		}
+	}
+}
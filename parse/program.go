@@ -0,0 +1,293 @@
+package parse
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jwowillo/viztransform/geometry"
+)
+
+// program runs the top-level expressions of Files while keeping track of the
+// names defined with 'let' and the files being included.
+type program struct {
+	// names defined so far and what they're defined as.
+	names map[string]*defined
+	// including are the absolute paths of the files being included with the
+	// outermost first.
+	including []string
+	// do evaluates a resolved top-level expression.
+	do func(x Expr) error
+	// check evaluates a resolved definition to make sure it's good.
+	check func(x Expr) error
+}
+
+// newProgram returns a program that evaluates top-level expressions with do
+// and checks definitions with check.
+func newProgram(do, check func(x Expr) error) *program {
+	return &program{
+		names: make(map[string]*defined),
+		do:    do,
+		check: check,
+	}
+}
+
+// runFile runs File f which was read from the file at path or wasn't read from
+// a file if path is empty.
+//
+// Included paths are relative to the file's directory or the current
+// directory if there isn't a file.
+func (p *program) runFile(f *File, path string) error {
+	if path == "" {
+		return p.run(f, ".")
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	p.including = []string{abs}
+	return p.run(f, filepath.Dir(path))
+}
+
+// run the top-level expressions of File f whose included paths are relative
+// to directory dir.
+//
+// Returns the first error from evaluating an expression with its snippet
+// filled in from f.
+func (p *program) run(f *File, dir string) error {
+	for _, x := range f.Exprs {
+		var err error
+		switch x := x.(type) {
+		case *LetExpr:
+			err = p.let(x)
+		case *IncludeExpr:
+			err = p.include(x, dir)
+		default:
+			err = p.do(p.resolve(x))
+		}
+		if err != nil {
+			return withSnippet(err, f.src)
+		}
+	}
+	return nil
+}
+
+// let defines the name in LetExpr x after checking its definition.
+//
+// Returns an *Error caused by ErrBadTransformation if the name is a keyword or
+// a constant and the error from checking the definition if it's bad.
+func (p *program) let(x *LetExpr) error {
+	if _, ok := constants[x.Name]; ok || keywords[x.Name] {
+		return errorAt(
+			x.NamePos,
+			ErrBadTransformation,
+			fmt.Sprintf("'%s' can't be defined", x.Name),
+		)
+	}
+	v := p.resolve(x.Value)
+	if err := p.check(v); err != nil {
+		return err
+	}
+	p.names[x.Name] = &defined{X: v, values: make(map[evaluation]value)}
+	return nil
+}
+
+// keywords that can't be defined.
+var keywords = map[string]bool{"let": true, "include": true}
+
+// include runs the file at the path in IncludeExpr x relative to directory dir
+// as if its lines were where x is.
+//
+// Returns an *Error caused by ErrIncludeCycle if the file is already being
+// included and by the error from opening the file if it can't be opened.
+// Errors in the file have their Path set to the file's path.
+func (p *program) include(x *IncludeExpr, dir string) error {
+	path := x.Path
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return errorAt(x.PathPos, err, hintInclude)
+	}
+	for i, including := range p.including {
+		if including == abs {
+			return errorAt(
+				x.PathPos,
+				ErrIncludeCycle,
+				cycle(append(p.including[i:], abs)),
+			)
+		}
+	}
+	r, err := os.Open(abs)
+	if err != nil {
+		return errorAt(x.PathPos, err, "")
+	}
+	defer r.Close()
+	f, err := ParseFile(r)
+	if err == nil {
+		p.including = append(p.including, abs)
+		err = p.run(f, filepath.Dir(path))
+		p.including = p.including[:len(p.including)-1]
+	}
+	if e, ok := err.(*Error); ok && e.Path == "" {
+		e.Path = path
+	}
+	return err
+}
+
+// cycle returns the hint for the paths including each other in a cycle.
+func cycle(paths []string) string {
+	names := make([]string, len(paths))
+	for i, path := range paths {
+		names[i] = fmt.Sprintf("'%s'", filepath.Base(path))
+	}
+	return strings.Join(names, " includes ")
+}
+
+// resolve returns Expr x with every IdentExpr of a defined name replaced by a
+// refExpr to what the name is defined as.
+func (p *program) resolve(x Expr) Expr {
+	switch x := x.(type) {
+	case *IdentExpr:
+		if d, ok := p.names[x.Name]; ok {
+			return &refExpr{IdentExpr: x, defined: d}
+		}
+	case *CallExpr:
+		c := *x
+		c.Args = make([]Expr, len(x.Args))
+		for i, arg := range x.Args {
+			c.Args[i] = p.resolve(arg)
+		}
+		return &c
+	case *LineExpr:
+		l := *x
+		l.A, l.B = p.resolve(x.A), p.resolve(x.B)
+		return &l
+	case *PointExpr:
+		pt := *x
+		pt.X, pt.Y = p.resolve(x.X), p.resolve(x.Y)
+		return &pt
+	case *VectorExpr:
+		v := *x
+		v.I, v.J = p.resolve(x.I), p.resolve(x.J)
+		return &v
+	case *ParenExpr:
+		e := *x
+		e.X = p.resolve(x.X)
+		return &e
+	case *UnaryExpr:
+		u := *x
+		u.X = p.resolve(x.X)
+		return &u
+	case *BinaryExpr:
+		b := *x
+		b.X, b.Y = p.resolve(x.X), p.resolve(x.Y)
+		return &b
+	}
+	return x
+}
+
+// refExpr is a use of a name defined with 'let' along with what it's defined
+// as.
+//
+// The refExpr is where the name is used so problems with using the definition
+// are reported there.
+type refExpr struct {
+	*IdentExpr
+	*defined
+}
+
+// defined is what a name is defined as with names in the definition already
+// resolved along with what the definition evaluated to so far.
+//
+// Every use of the name shares the defined so the definition is evaluated
+// once for each way it's evaluated instead of once for every use, which would
+// take exponentially long for definitions using earlier ones more than once.
+type defined struct {
+	X      Expr
+	values map[evaluation]value
+}
+
+// evaluation is a way a definition is evaluated.
+type evaluation struct {
+	// kind of thing the definition is evaluated to.
+	kind string
+	// tol is the geometry.Tolerance the definition is evaluated within.
+	tol geometry.Tolerance
+}
+
+// value is what a definition evaluated to.
+type value struct {
+	v   interface{}
+	err error
+}
+
+// value returns what the definition in refExpr r evaluates to the way e says
+// with eval, which is only called the first time r's definition is evaluated
+// that way.
+//
+// The error is moved to where the name is used like with used.
+func (r *refExpr) value(
+	e evaluation,
+	eval func(x Expr) (interface{}, error),
+) (interface{}, error) {
+	v, ok := r.values[e]
+	if !ok {
+		v.v, v.err = eval(r.X)
+		r.values[e] = v
+	}
+	return v.v, r.used(v.err)
+}
+
+// used returns err at the Position of the name in refExpr r if err is an
+// *Error since the definition was already checked and the problem is with
+// where it's used.
+func (r *refExpr) used(err error) error {
+	e, ok := err.(*Error)
+	if !ok {
+		return err
+	}
+	moved := *e
+	moved.Line, moved.Column = r.Pos().Line, r.Pos().Column
+	moved.Snippet, moved.Path = "", ""
+	return &moved
+}
+
+// check returns a func that checks a definition by evaluating it as what it
+// looks like with the evaluators passed.
+//
+// Definitions that aren't a transformation, geometry.Line, geometry.Point, or
+// geometry.Vector are numbers which are always checked with number since
+// numbers that can't be exact can still be angles.
+func check(
+	transformation, line, point, vector func(x Expr) error,
+) func(x Expr) error {
+	return func(x Expr) error {
+		switch definition(x).(type) {
+		case *CallExpr:
+			return transformation(x)
+		case *LineExpr:
+			return line(x)
+		case *PointExpr:
+			return point(x)
+		case *VectorExpr:
+			return vector(x)
+		}
+		_, err := number(x)
+		return err
+	}
+}
+
+// definition returns what Expr x is once every refExpr is replaced by its
+// definition.
+func definition(x Expr) Expr {
+	for {
+		r, ok := x.(*refExpr)
+		if !ok {
+			return x
+		}
+		x = r.X
+	}
+}
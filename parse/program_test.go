@@ -0,0 +1,181 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLet checks names defined with 'let' are used on later lines and bad
+// definitions give *Errors at the problem.
+func TestLet(t *testing.T) {
+	cases := []struct {
+		x            string
+		err          error
+		line, column int
+		hint         string
+	}{
+		{x: "let a = 2\nlet v = <a 0>\nTranslation(v)"},
+		{x: "let t = Translation(<2 0>)\nCompose(t)"},
+		{x: "let a = 1\nlet a = 2\nTranslation(<a 0>)"},
+		{x: "let a = (1 0)\nlet l = {a (1 1)}\n" +
+			"LineReflection({(0 0) (0 1)})\nLineReflection(l)"},
+		{
+			x:   "Translation(<a 0>)\nlet a = 2",
+			err: ErrBadVector, line: 1, column: 14, hint: undefined("a"),
+		},
+		{
+			x:   "let pi = 3",
+			err: ErrBadTransformation, line: 1, column: 5,
+			hint: "'pi' can't be defined",
+		},
+		{
+			x:   "let include = 3",
+			err: ErrBadTransformation, line: 1, column: 5,
+			hint: "'include' can't be defined",
+		},
+		{
+			x:   "\nlet a = (1 x)",
+			err: ErrBadPoint, line: 2, column: 12,
+			hint: undefined("x"),
+		},
+	}
+	for _, c := range cases {
+		got, err := Transformation(strings.NewReader(c.x))
+		if c.err == nil {
+			if err != nil {
+				t.Errorf("%q gives %v", c.x, err)
+				continue
+			}
+			checkMatrix(t, c.x, testTranslation(2, 0), got)
+			continue
+		}
+		checkAt(t, c.x, err, c.err, c.line, c.column)
+		checkHint(t, c.x, err, c.hint)
+	}
+}
+
+// TestLetChain checks definitions using an earlier one more than once are each
+// evaluated once instead of once for every use.
+func TestLetChain(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("let a0 = 2\n")
+	const n = 64
+	for i := 1; i < n; i++ {
+		fmt.Fprintf(&b, "let a%d = (a%d + a%d) / 2\n", i, i-1, i-1)
+	}
+	fmt.Fprintf(&b, "Translation(<a%d 0>)", n-1)
+	x := b.String()
+	got, err := Transformation(strings.NewReader(x))
+	if err != nil {
+		t.Fatalf("chain of %d definitions gives %v", n, err)
+	}
+	checkMatrix(t, "chain", testTranslation(2, 0), got)
+	if _, err := ExactTransformation(strings.NewReader(x)); err != nil {
+		t.Errorf("chain of %d definitions gives %v exactly", n, err)
+	}
+}
+
+// TestIncludeRelative checks included paths are relative to the including
+// file and that names defined in an included file are used after it.
+func TestIncludeRelative(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"main.txt":    "include \"sub/b.txt\"\nTranslation(v)\n",
+		"sub/b.txt":   "include \"c.txt\"\nlet v = <a 0>\n",
+		"sub/c.txt":   "let a = 1\n",
+		"outside.txt": "include \"" + filepath.Join(dir, "main.txt") + "\"\n",
+	})
+	got, err := TransformationFile(filepath.Join(dir, "main.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkMatrix(t, "main.txt", testTranslation(1, 0), got)
+	x := "include \"" + filepath.Join(dir, "main.txt") + "\""
+	got, err = Transformation(strings.NewReader(x))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkMatrix(t, x, testTranslation(1, 0), got)
+}
+
+// TestIncludeCycle checks files including themselves give *Errors at the
+// include that closes the cycle naming every file in it.
+func TestIncludeCycle(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.txt":     "Translation(<1 0>)\n  include \"b.txt\"\n",
+		"b.txt":     "include \"sub/c.txt\"\n",
+		"sub/c.txt": "include \"../a.txt\"\n",
+		"self.txt":  "include \"self.txt\"\n",
+	})
+	_, err := TransformationFile(filepath.Join(dir, "a.txt"))
+	checkAt(t, "a.txt", err, ErrIncludeCycle, 1, 9)
+	checkHint(t, "a.txt", err, "'a.txt' includes 'b.txt' includes 'c.txt' "+
+		"includes 'a.txt'")
+	checkPath(t, "a.txt", err, filepath.Join(dir, "sub", "c.txt"))
+	_, err = TransformationFile(filepath.Join(dir, "self.txt"))
+	checkAt(t, "self.txt", err, ErrIncludeCycle, 1, 9)
+	checkHint(t, "self.txt", err, "'self.txt' includes 'self.txt'")
+}
+
+// TestIncludeErrors checks problems in included files are reported in the
+// included file with its line and problems with the include itself are
+// reported at its path.
+func TestIncludeErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"main.txt":    "\n  include \"sub/bad.txt\"\n",
+		"sub/bad.txt": "Translation(<1 0>)\nRotation((0 0), x)\n",
+		"missing.txt": "# nothing\ninclude \"nowhere.txt\"\n",
+	})
+	_, err := TransformationFile(filepath.Join(dir, "main.txt"))
+	checkAt(t, "main.txt", err, ErrBadAngle, 2, 17)
+	checkPath(t, "main.txt", err, filepath.Join(dir, "sub", "bad.txt"))
+	var e *Error
+	if errors.As(err, &e) && e.Snippet != "Rotation((0 0), x)" {
+		t.Errorf("main.txt gives snippet %q but should give %q",
+			e.Snippet, "Rotation((0 0), x)")
+	}
+	_, err = TransformationFile(filepath.Join(dir, "missing.txt"))
+	checkAt(t, "missing.txt", err, fs.ErrNotExist, 2, 9)
+	checkPath(t, "missing.txt", err, "")
+}
+
+// writeFiles writes the files with the contents in files under the directory
+// dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, x := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(x), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// checkHint fails the test if err from parsing x isn't an *Error with hint.
+func checkHint(t *testing.T, x string, err error, hint string) {
+	t.Helper()
+	var e *Error
+	if errors.As(err, &e) && e.Hint != hint {
+		t.Errorf("%q gives hint %q but should give %q", x, e.Hint, hint)
+	}
+}
+
+// checkPath fails the test if err from parsing x isn't an *Error in the
+// included file at path.
+func checkPath(t *testing.T, x string, err error, path string) {
+	t.Helper()
+	var e *Error
+	if errors.As(err, &e) && e.Path != path {
+		t.Errorf("%q gives an *Error in %q but should in %q", x, e.Path, path)
+	}
+}
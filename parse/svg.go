@@ -0,0 +1,843 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/jwowillo/viztransform/geometry"
+	"github.com/jwowillo/viztransform/transform"
+)
+
+var (
+	// ErrBadSVG is returned when an SVG transform-attribute's string is bad.
+	ErrBadSVG = errors.New("bad SVG transform-string")
+	// ErrBadCSS is returned when a CSS transform-property's string is bad.
+	ErrBadCSS = errors.New("bad CSS transform-string")
+)
+
+// SVG parses a transform.Transformation from the string x of an SVG
+// transform-attribute like 'translate(10 20) rotate(45)'.
+//
+// The string is a list of the functions 'matrix(a b c d e f)', 'translate(x
+// [y])', 'scale(x [y])', 'rotate(degrees [x y])', 'skewX(degrees)', and
+// 'skewY(degrees)' separated by spaces or commas with arguments separated the
+// same way. The functions are done from last to first like SVG does. The
+// whole attribute like 'transform="rotate(45)"' can also be passed.
+// Coordinates are used as they are so rotations that look clockwise in SVG
+// since its y-axis points down are counter-clockwise ones.
+//
+// Returns an *Error caused by ErrBadSVG if the string doesn't fit the pattern
+// or passes the wrong arguments to a function and by transform.ErrNotIsometry
+// if a function changes distances like 'scale(2)' does. Scales by 1 and -1 and
+// skews by 0 are kept.
+func SVG(x string) (transform.Transformation, error) {
+	return SVGWithin(x, geometry.DefaultTolerance())
+}
+
+// SVGWithin is SVG where geometry.Numbers are compared within
+// geometry.Tolerance tol when checking if transform.Transformations are
+// degenerate.
+//
+// A function is checked to be an isometry within tol or within 1e-3 if that's
+// looser since matrices in SVG files are often written with few digits. The
+// function is then taken to be the isometry closest to it.
+func SVGWithin(
+	x string,
+	tol geometry.Tolerance,
+) (transform.Transformation, error) {
+	t, err := svg.transformation(x, tol)
+	return t, withSnippet(err, x)
+}
+
+// CSS parses a transform.Transformation from the string x of a CSS
+// transform-property like 'translate(10px, 20px) rotate(45deg)'.
+//
+// The string is 'none' or a list of the 2D CSS transform-functions separated
+// by spaces. The functions are 'matrix', 'translate', 'translateX',
+// 'translateY', 'scale', 'scaleX', 'scaleY', 'rotate', 'skew', 'skewX', and
+// 'skewY' with arguments separated by commas. Lengths are in 'px', angles are
+// in 'deg', 'rad', 'grad', or 'turn', and scales can be percentages. The whole
+// declaration like 'transform: rotate(45deg);' can also be passed. The
+// transform-origin is taken to be (0 0) and coordinates are used as they are
+// like SVG describes.
+//
+// Returns an *Error caused by ErrBadCSS or transform.ErrNotIsometry like SVG
+// does.
+func CSS(x string) (transform.Transformation, error) {
+	return CSSWithin(x, geometry.DefaultTolerance())
+}
+
+// CSSWithin is CSS where geometry.Numbers are compared within
+// geometry.Tolerance tol as described by SVGWithin.
+func CSSWithin(
+	x string,
+	tol geometry.Tolerance,
+) (transform.Transformation, error) {
+	t, err := css.transformation(x, tol)
+	return t, withSnippet(err, x)
+}
+
+// FormatSVG writes transform.Transformation t to io.Writer w as the string of
+// an SVG transform-attribute.
+//
+// t is written as 'translate(i j)' if it's a translation or does nothing,
+// 'rotate(degrees)' or 'rotate(degrees x y)' if it's a rotation, 'scale(1 -1)'
+// or 'scale(-1 1)' if it reflects across an axis, and 'rotate(degrees)
+// scale(1 -1)' if it reflects across another line through (0 0). Reflections
+// across lines that don't go through (0 0) and glide-reflections are written
+// with a 'translate(i j)' before. Numbers are written like FormatNumber writes
+// them after being snapped to the decimal with the fewest digits that's equal
+// to them within the geometry.DefaultTolerance so float noise isn't written.
+//
+// Returns the transform.InstabilityError transform.DescribeE does if t can't
+// be described and any error from writing to w.
+func FormatSVG(w io.Writer, t transform.Transformation) error {
+	return FormatSVGWithin(w, t, geometry.DefaultTolerance())
+}
+
+// FormatSVGWithin is FormatSVG where geometry.Numbers are compared within
+// geometry.Tolerance tol when working out what t is and snapping numbers.
+func FormatSVGWithin(
+	w io.Writer,
+	t transform.Transformation,
+	tol geometry.Tolerance,
+) error {
+	x, err := svg.format(t, tol)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, x)
+	return err
+}
+
+// FormatCSS writes transform.Transformation t to io.Writer w as the string of a
+// CSS transform-property.
+//
+// t is written as 'none' if it does nothing, 'translate(ipx, jpx)' if it's a
+// translation, 'rotate(degreesdeg)' if it's a rotation around (0 0), and like
+// FormatSVG writes it with CSS units and separators otherwise. A rotation
+// around another point is written as a rotation between translations to and
+// from the point. Numbers are snapped like FormatSVG snaps them.
+//
+// Returns errors like FormatSVG does.
+func FormatCSS(w io.Writer, t transform.Transformation) error {
+	return FormatCSSWithin(w, t, geometry.DefaultTolerance())
+}
+
+// FormatCSSWithin is FormatCSS where geometry.Numbers are compared within
+// geometry.Tolerance tol when working out what t is and snapping numbers.
+func FormatCSSWithin(
+	w io.Writer,
+	t transform.Transformation,
+	tol geometry.Tolerance,
+) error {
+	x, err := css.format(t, tol)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, x)
+	return err
+}
+
+// dialect of transform-strings.
+type dialect struct {
+	// err is what's wrong with a bad string.
+	err error
+	// hint for a string that doesn't fit the pattern.
+	hint string
+	// property is the name of the attribute or property before the
+	// transform-string which is followed by assign.
+	property string
+	assign   byte
+	// functions that can be called.
+	functions map[string]function
+	// names of the functions for hints.
+	names string
+	// value converts an argument of a kind to a number or returns a hint
+	// saying why it can't.
+	value func(a argument, k kind) (float64, string)
+	// none is the string of a transform.Transformation that does nothing.
+	none string
+	// keyword is true if none is a keyword that can be read too.
+	keyword bool
+	// separator goes between written arguments.
+	separator string
+	// length and angle are the units of written lengths and angles.
+	length, angle string
+	// center is true if rotations can be written around a point.
+	center bool
+}
+
+// function that can be called in a transform-string.
+type function struct {
+	// kinds of each argument.
+	kinds []kind
+	// counts of arguments that can be passed.
+	counts []int
+	// matrix returns the transform.Matrix for arguments converted to numbers
+	// with angles in radians.
+	matrix func(vs []float64) transform.Matrix
+}
+
+// kind of argument.
+type kind int
+
+// kinds of arguments.
+const (
+	// kindNumber is a number without a unit.
+	kindNumber kind = iota
+	// kindScale is a number that can be a percentage.
+	kindScale
+	// kindLength is a distance.
+	kindLength
+	// kindAngle is an angle.
+	kindAngle
+)
+
+// call of a function in a transform-string.
+type call struct {
+	name string
+	// pos is the offset of the name in the string.
+	pos  int
+	args []argument
+}
+
+// argument of a call.
+type argument struct {
+	value float64
+	unit  string
+	// pos is the offset of the argument in the string.
+	pos int
+}
+
+// matrixKinds are the kinds of the 6 arguments of 'matrix' which are all
+// kindNumber.
+var matrixKinds = make([]kind, 6)
+
+// svg is the dialect of SVG transform-attributes.
+var svg = dialect{
+	err:      ErrBadSVG,
+	hint:     "SVG transforms look like 'translate(10 20) rotate(45)'",
+	property: "transform",
+	assign:   '=',
+	functions: map[string]function{
+		"matrix": {
+			kinds:  matrixKinds,
+			counts: []int{6},
+			matrix: matrix,
+		},
+		"translate": {
+			kinds:  []kind{kindLength, kindLength},
+			counts: []int{1, 2},
+			matrix: translate,
+		},
+		"scale": {
+			kinds:  []kind{kindScale, kindScale},
+			counts: []int{1, 2},
+			matrix: scale,
+		},
+		"rotate": {
+			kinds:  []kind{kindAngle, kindLength, kindLength},
+			counts: []int{1, 3},
+			matrix: rotate,
+		},
+		"skewX": {kinds: []kind{kindAngle}, counts: []int{1}, matrix: skewX},
+		"skewY": {kinds: []kind{kindAngle}, counts: []int{1}, matrix: skewY},
+	},
+	names: "matrix, translate, scale, rotate, skewX, or skewY",
+	value: func(a argument, k kind) (float64, string) {
+		if a.unit != "" {
+			return 0, "SVG numbers don't have units"
+		}
+		if k == kindAngle {
+			return a.value * math.Pi / 180, ""
+		}
+		return a.value, ""
+	},
+	none:      "translate(0 0)",
+	separator: " ",
+	center:    true,
+}
+
+// css is the dialect of CSS transform-properties.
+var css = dialect{
+	err:      ErrBadCSS,
+	hint:     "CSS transforms look like 'translate(10px, 20px) rotate(45deg)'",
+	property: "transform",
+	assign:   ':',
+	functions: map[string]function{
+		"matrix": {
+			kinds:  matrixKinds,
+			counts: []int{6},
+			matrix: matrix,
+		},
+		"translate": {
+			kinds:  []kind{kindLength, kindLength},
+			counts: []int{1, 2},
+			matrix: translate,
+		},
+		"translateX": {
+			kinds:  []kind{kindLength},
+			counts: []int{1},
+			matrix: translate,
+		},
+		"translateY": {
+			kinds:  []kind{kindLength},
+			counts: []int{1},
+			matrix: func(vs []float64) transform.Matrix {
+				return translate([]float64{0, vs[0]})
+			},
+		},
+		"scale": {
+			kinds:  []kind{kindScale, kindScale},
+			counts: []int{1, 2},
+			matrix: scale,
+		},
+		"scaleX": {
+			kinds:  []kind{kindScale},
+			counts: []int{1},
+			matrix: func(vs []float64) transform.Matrix {
+				return scale([]float64{vs[0], 1})
+			},
+		},
+		"scaleY": {
+			kinds:  []kind{kindScale},
+			counts: []int{1},
+			matrix: func(vs []float64) transform.Matrix {
+				return scale([]float64{1, vs[0]})
+			},
+		},
+		"rotate": {kinds: []kind{kindAngle}, counts: []int{1}, matrix: rotate},
+		"skew": {
+			kinds:  []kind{kindAngle, kindAngle},
+			counts: []int{1, 2},
+			matrix: skew,
+		},
+		"skewX": {kinds: []kind{kindAngle}, counts: []int{1}, matrix: skewX},
+		"skewY": {kinds: []kind{kindAngle}, counts: []int{1}, matrix: skewY},
+	},
+	names: "matrix, translate, translateX, translateY, scale, scaleX, " +
+		"scaleY, rotate, skew, skewX, or skewY",
+	value: func(a argument, k kind) (float64, string) {
+		switch {
+		case k == kindScale && a.unit == "%":
+			return a.value / 100, ""
+		case k == kindLength && a.unit == "px":
+			return a.value, ""
+		case k == kindAngle && a.unit != "":
+			turns, ok := turns[a.unit]
+			if !ok {
+				return 0, hintCSSAngle
+			}
+			return a.value * turns * 2 * math.Pi, ""
+		case k == kindAngle && a.value != 0:
+			return 0, hintCSSAngle
+		case a.unit != "" && k == kindLength:
+			return 0, "lengths must be in 'px' since other units depend " +
+				"on the page"
+		case a.unit != "":
+			return 0, "the number can't have a unit"
+		}
+		return a.value, ""
+	},
+	none:      "none",
+	keyword:   true,
+	separator: ", ",
+	length:    "px",
+	angle:     "deg",
+}
+
+// turns in one of each CSS angle unit.
+var turns = map[string]float64{
+	"deg":  1.0 / 360,
+	"grad": 1.0 / 400,
+	"rad":  1 / (2 * math.Pi),
+	"turn": 1,
+}
+
+// hintCSSAngle is the hint for a CSS angle without a unit it can have.
+const hintCSSAngle = "angles must be in 'deg', 'rad', 'grad', or 'turn'"
+
+// matrix returns the transform.Matrix with values vs in the order a, b, c, d,
+// e, and f of SVG and CSS.
+func matrix(vs []float64) transform.Matrix {
+	return transform.Matrix{
+		{geometry.Number(vs[0]), geometry.Number(vs[2]), geometry.Number(vs[4])},
+		{geometry.Number(vs[1]), geometry.Number(vs[3]), geometry.Number(vs[5])},
+		{0, 0, 1},
+	}
+}
+
+// translate returns the transform.Matrix translating by vs[0] along x and vs[1]
+// along y if it's passed.
+func translate(vs []float64) transform.Matrix {
+	return matrix([]float64{1, 0, 0, 1, vs[0], or(vs, 1, 0)})
+}
+
+// scale returns the transform.Matrix scaling by vs[0] along x and vs[1] along
+// y or vs[0] if it isn't passed.
+func scale(vs []float64) transform.Matrix {
+	return matrix([]float64{vs[0], 0, 0, or(vs, 1, vs[0]), 0, 0})
+}
+
+// rotate returns the transform.Matrix rotating by vs[0] radians around the
+// point (vs[1] vs[2]) or (0 0) if it isn't passed.
+func rotate(vs []float64) transform.Matrix {
+	cos, sin := math.Cos(vs[0]), math.Sin(vs[0])
+	x, y := or(vs, 1, 0), or(vs, 2, 0)
+	return matrix([]float64{
+		cos, sin,
+		-sin, cos,
+		x - cos*x + sin*y, y - sin*x - cos*y,
+	})
+}
+
+// skew returns the transform.Matrix skewing by vs[0] radians along x and vs[1]
+// radians along y if it's passed.
+func skew(vs []float64) transform.Matrix {
+	return matrix([]float64{1, math.Tan(or(vs, 1, 0)), math.Tan(vs[0]), 1, 0, 0})
+}
+
+// skewX returns the transform.Matrix skewing by vs[0] radians along x.
+func skewX(vs []float64) transform.Matrix {
+	return skew([]float64{vs[0], 0})
+}
+
+// skewY returns the transform.Matrix skewing by vs[0] radians along y.
+func skewY(vs []float64) transform.Matrix {
+	return skew([]float64{0, vs[0]})
+}
+
+// or returns vs[i] or n if there isn't one.
+func or(vs []float64, i int, n float64) float64 {
+	if i < len(vs) {
+		return vs[i]
+	}
+	return n
+}
+
+// transformation parses a transform.Transformation from the transform-string x
+// in the dialect.
+//
+// Returns an *Error as described by SVG.
+func (d dialect) transformation(
+	x string,
+	tol geometry.Tolerance,
+) (transform.Transformation, error) {
+	i, end, err := d.unwrap(x)
+	if err != nil {
+		return nil, err
+	}
+	if d.keyword && strings.TrimSpace(x[i:end]) == d.none {
+		return transform.NoTransformation(), nil
+	}
+	cs, err := d.calls(x, i, end)
+	if err != nil {
+		return nil, err
+	}
+	var t transform.Transformation
+	for _, c := range cs {
+		nt, err := d.call(x, c, tol)
+		if err != nil {
+			return nil, err
+		}
+		t = transform.Compose(nt, t)
+	}
+	return t, nil
+}
+
+// unwrap returns the offsets of the start and end of the transform-string in
+// x which is all of x without trailing spaces unless x is the whole attribute
+// or declaration.
+//
+// Returns an *Error caused by the dialect's err if an attribute's quotes
+// aren't closed.
+func (d dialect) unwrap(x string) (int, int, error) {
+	end := len(strings.TrimRight(x, spaces))
+	i := skipSpaces(x, 0, end)
+	if !strings.HasPrefix(x[i:], d.property) {
+		return 0, end, nil
+	}
+	i = skipSpaces(x, i+len(d.property), end)
+	if i == end || x[i] != d.assign {
+		return 0, end, nil
+	}
+	i = skipSpaces(x, i+1, end)
+	if d.assign == ':' {
+		return i, len(strings.TrimSuffix(x[:end], ";")), nil
+	}
+	if i == end || x[i] != '"' && x[i] != '\'' {
+		return 0, 0, d.errorAt(x, i, "the attribute's value must be quoted")
+	}
+	if end-1 == i || x[end-1] != x[i] {
+		return 0, 0, d.errorAt(
+			x,
+			end,
+			fmt.Sprintf("the attribute's value must end with %c", x[i]),
+		)
+	}
+	return i + 1, end - 1, nil
+}
+
+// calls in x between offsets i and end.
+//
+// Returns an *Error caused by the dialect's err at the first thing that isn't
+// part of a call.
+func (d dialect) calls(x string, i, end int) ([]call, error) {
+	var cs []call
+	for {
+		i = skipSeparators(x, i, end)
+		if i == end {
+			return cs, nil
+		}
+		c := call{pos: i}
+		for i < end && isNameByte(x[i]) {
+			i++
+		}
+		if i == c.pos {
+			return nil, d.errorAt(x, i, d.hint)
+		}
+		c.name = x[c.pos:i]
+		if i == end || x[i] != '(' {
+			return nil, d.errorAt(
+				x,
+				i,
+				fmt.Sprintf("'%s' must be followed by '('", c.name),
+			)
+		}
+		i++
+		for {
+			i = skipSpaces(x, i, end)
+			if i < end && x[i] == ')' {
+				i++
+				break
+			}
+			if len(c.args) > 0 && i < end && x[i] == ',' {
+				i = skipSpaces(x, i+1, end)
+			}
+			if i == end {
+				return nil, d.errorAt(
+					x,
+					i,
+					fmt.Sprintf("'%s' must end with ')'", c.name),
+				)
+			}
+			a, next, ok := scanArgument(x, i, end)
+			if !ok {
+				return nil, d.errorAt(x, i, d.hint)
+			}
+			c.args = append(c.args, a)
+			i = next
+		}
+		cs = append(cs, c)
+	}
+}
+
+// call returns the transform.Transformation of call c in x.
+//
+// Returns an *Error caused by the dialect's err if c's function doesn't exist,
+// c doesn't pass the right arguments, or an argument has the wrong unit and by
+// transform.ErrNotIsometry if c changes distances within geometry.Tolerance
+// tol.
+func (d dialect) call(
+	x string,
+	c call,
+	tol geometry.Tolerance,
+) (transform.Transformation, error) {
+	f, ok := d.functions[c.name]
+	if !ok {
+		return nil, d.errorAt(
+			x,
+			c.pos,
+			fmt.Sprintf("'%s' isn't %s", c.name, d.names),
+		)
+	}
+	if !hasCount(f.counts, len(c.args)) {
+		return nil, d.errorAt(
+			x,
+			c.pos,
+			fmt.Sprintf(
+				"'%s' takes %s arguments but got %d",
+				c.name,
+				counts(f.counts),
+				len(c.args),
+			),
+		)
+	}
+	vs := make([]float64, len(c.args))
+	for i, a := range c.args {
+		v, hint := d.value(a, f.kinds[i])
+		if hint != "" {
+			return nil, d.errorAt(x, a.pos, hint)
+		}
+		vs[i] = v
+	}
+	m := f.matrix(vs)
+	if !transform.IsIsometryWithin(m, looser(tol)) {
+		return nil, problemAt(
+			offsetPosition([]byte(x), c.pos),
+			transform.ErrNotIsometry,
+			fmt.Sprintf(
+				"'%s' must keep distances the same like "+
+					"'scale(-1%s1)' does",
+				c.name,
+				d.separator,
+			),
+		)
+	}
+	return transform.FromMatrixWithin(isometry(m), tol)
+}
+
+// isometryTolerance is the loosest geometry.Tolerance a function's
+// transform.Matrix is checked to be an isometry within since matrices in SVG
+// and CSS are often written with as few as 3 digits like 'matrix(0.707 0.707
+// -0.707 0.707 0 0)'.
+var isometryTolerance = geometry.Tolerance{Absolute: 1e-3}
+
+// looser returns geometry.Tolerance tol with an Absolute part at least as
+// large as isometryTolerance's.
+func looser(tol geometry.Tolerance) geometry.Tolerance {
+	tol.Absolute = geometry.Number(math.Max(
+		float64(tol.Absolute),
+		float64(isometryTolerance.Absolute),
+	))
+	return tol
+}
+
+// isometry returns the transform.Matrix that rotates or reflects like the
+// first column of the transform.Matrix m and translates like m.
+//
+// m must be an isometry within a geometry.Tolerance so the result is the
+// isometry closest to it.
+func isometry(m transform.Matrix) transform.Matrix {
+	rads := math.Atan2(float64(m[1][0]), float64(m[0][0]))
+	cos, sin := math.Cos(rads), math.Sin(rads)
+	e, f := float64(m[0][2]), float64(m[1][2])
+	if m[0][0]*m[1][1]-m[0][1]*m[1][0] < 0 {
+		return matrix([]float64{cos, sin, sin, -cos, e, f})
+	}
+	return matrix([]float64{cos, sin, -sin, cos, e, f})
+}
+
+// hasCount returns true if n is one of counts.
+func hasCount(counts []int, n int) bool {
+	for _, count := range counts {
+		if count == n {
+			return true
+		}
+	}
+	return false
+}
+
+// counts returns the string of counts like '1 or 2'.
+func counts(ns []int) string {
+	xs := make([]string, len(ns))
+	for i, n := range ns {
+		xs[i] = strconv.Itoa(n)
+	}
+	return strings.Join(xs, " or ")
+}
+
+// errorAt returns an *Error caused by the dialect's err at offset i of x with
+// hint.
+func (d dialect) errorAt(x string, i int, hint string) *Error {
+	return problemAt(offsetPosition([]byte(x), i), d.err, hint)
+}
+
+// scanArgument scans the argument at offset i of x before offset end and
+// returns it along with the offset after it.
+//
+// Returns false if there isn't a number at i.
+func scanArgument(x string, i, end int) (argument, int, bool) {
+	start := i
+	if i < end && (x[i] == '+' || x[i] == '-') {
+		i++
+	}
+	digits := 0
+	for i < end && isDigit(x[i]) {
+		i, digits = i+1, digits+1
+	}
+	if i < end && x[i] == '.' {
+		i++
+		for i < end && isDigit(x[i]) {
+			i, digits = i+1, digits+1
+		}
+	}
+	if digits == 0 {
+		return argument{}, start, false
+	}
+	if i < end && (x[i] == 'e' || x[i] == 'E') {
+		j := i + 1
+		if j < end && (x[j] == '+' || x[j] == '-') {
+			j++
+		}
+		if j < end && isDigit(x[j]) {
+			for i = j; i < end && isDigit(x[i]); i++ {
+			}
+		}
+	}
+	v, err := strconv.ParseFloat(x[start:i], 64)
+	if err != nil {
+		return argument{}, start, false
+	}
+	a := argument{value: v, pos: start}
+	unit := i
+	for i < end && (isLetter(x[i]) || x[i] == '%') {
+		i++
+	}
+	a.unit = x[unit:i]
+	return a, i, true
+}
+
+// spaces that can separate parts of a transform-string.
+const spaces = " \t\r\n\f"
+
+// skipSpaces returns the offset of the first byte in x at or after offset i and
+// before offset end that isn't a space.
+func skipSpaces(x string, i, end int) int {
+	for i < end && strings.IndexByte(spaces, x[i]) >= 0 {
+		i++
+	}
+	return i
+}
+
+// skipSeparators is skipSpaces that also skips commas.
+func skipSeparators(x string, i, end int) int {
+	for i < end && strings.IndexByte(spaces+",", x[i]) >= 0 {
+		i++
+	}
+	return i
+}
+
+// isNameByte returns true if b can be part of a function's name.
+func isNameByte(b byte) bool {
+	return isLetter(b) || isDigit(b)
+}
+
+// isLetter returns true if b is an ASCII letter.
+func isLetter(b byte) bool {
+	return 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z'
+}
+
+// format returns the transform-string in the dialect of
+// transform.Transformation t whose geometry.Numbers are compared within
+// geometry.Tolerance tol.
+//
+// Returns the transform.InstabilityError transform.DescribeWithinE does if t
+// can't be described.
+func (d dialect) format(
+	t transform.Transformation,
+	tol geometry.Tolerance,
+) (string, error) {
+	p, err := transform.DescribeWithinE(t, tol)
+	if err != nil {
+		return "", err
+	}
+	return d.formatParams(t, p, tol), nil
+}
+
+// formatParams returns the transform-string in the dialect of
+// transform.Transformation t described by transform.Params p whose
+// geometry.Numbers are compared within geometry.Tolerance tol.
+//
+// Numbers are snapped to the shortest decimals within tol so float noise isn't
+// written. Reflections and glide-reflections are written as a scale by -1
+// that's rotated and translated into place.
+func (d dialect) formatParams(
+	t transform.Transformation,
+	p transform.Params,
+	tol geometry.Tolerance,
+) string {
+	switch p := p.(type) {
+	case transform.NoTransformationParams:
+		return d.none
+	case transform.TranslationParams:
+		return d.translate(snap(p.Vector.I, tol), snap(p.Vector.J, tol))
+	case transform.RotationParams:
+		degrees := d.degrees(float64(p.Angle), tol)
+		x, y := snap(p.Center.X, tol), snap(p.Center.Y, tol)
+		if x == 0 && y == 0 {
+			return fmt.Sprintf("rotate(%s)", degrees)
+		}
+		if d.center {
+			return fmt.Sprintf(
+				"rotate(%s)",
+				d.join(degrees, FormatNumber(x), FormatNumber(y)),
+			)
+		}
+		return fmt.Sprintf(
+			"%s rotate(%s) %s",
+			d.translate(x, y),
+			degrees,
+			d.translate(-x, -y),
+		)
+	}
+	m := transform.ToMatrix(t)
+	rads := math.Atan2(float64(m[1][0]), float64(m[0][0]))
+	reflect := d.reflect(rads, tol)
+	x, y := snap(m[0][2], tol), snap(m[1][2], tol)
+	if x == 0 && y == 0 {
+		return reflect
+	}
+	return fmt.Sprintf("%s %s", d.translate(x, y), reflect)
+}
+
+// reflect returns the transform-string in the dialect reflecting across the
+// geometry.Line through the origin at rads/2 radians as 'scale(1 -1)' rotated
+// by rads.
+//
+// Reflections across the axes are written as just the scale.
+func (d dialect) reflect(rads float64, tol geometry.Tolerance) string {
+	degrees := d.degrees(rads, tol)
+	switch degrees {
+	case "0" + d.angle:
+		return fmt.Sprintf("scale(%s)", d.join("1", "-1"))
+	case "180" + d.angle, "-180" + d.angle:
+		return fmt.Sprintf("scale(%s)", d.join("-1", "1"))
+	}
+	return fmt.Sprintf("rotate(%s) scale(%s)", degrees, d.join("1", "-1"))
+}
+
+// degrees returns the string in the dialect of rads radians as degrees in
+// [-180, 180] snapped within geometry.Tolerance tol.
+func (d dialect) degrees(rads float64, tol geometry.Tolerance) string {
+	degrees := math.Remainder(rads, 2*math.Pi) * 180 / math.Pi
+	return FormatNumber(snap(geometry.Number(degrees), tol)) + d.angle
+}
+
+// snap returns the decimal with the fewest digits after the point that equals
+// geometry.Number n within geometry.Tolerance tol.
+//
+// -0 is returned as 0.
+func snap(n geometry.Number, tol geometry.Tolerance) geometry.Number {
+	for digits := 0; digits < 17; digits++ {
+		x := strconv.FormatFloat(float64(n), 'f', digits, 64)
+		v, err := strconv.ParseFloat(x, 64)
+		if err == nil && tol.AreEqual(geometry.Number(v), n) {
+			n = geometry.Number(v)
+			break
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return n
+}
+
+// translate returns the transform-string in the dialect translating by x and
+// y.
+func (d dialect) translate(x, y geometry.Number) string {
+	return fmt.Sprintf(
+		"translate(%s)",
+		d.join(FormatNumber(x)+d.length, FormatNumber(y)+d.length),
+	)
+}
+
+// join arguments xs with the dialect's separator.
+func (d dialect) join(xs ...string) string {
+	return strings.Join(xs, d.separator)
+}
@@ -0,0 +1,244 @@
+package parse
+
+import (
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/jwowillo/viztransform/geometry"
+	"github.com/jwowillo/viztransform/transform"
+)
+
+// TestSVG checks SVG transform-strings are parsed into the
+// transform.Transformations they describe.
+func TestSVG(t *testing.T) {
+	cases := []struct {
+		x    string
+		want transform.Transformation
+	}{
+		{"translate(10 20)", testTranslation(10, 20)},
+		{"translate(10)", testTranslation(10, 0)},
+		{"rotate(90)", testRotation(0, 0, 90)},
+		{"rotate(90 1 2)", testRotation(1, 2, 90)},
+		{"scale(-1)", testRotation(0, 0, 180)},
+		{"scale(1 -1)", testReflection(0, 0, 1, 0)},
+		{"scale(-1,1)", testReflection(0, 0, 0, 1)},
+		{"skewX(0) scale(1)", transform.NoTransformation()},
+		{"matrix(0.7071 0.7071 -0.7071 0.7071 0 0)", testRotation(0, 0, 45)},
+		{"matrix(0.707,0.707,-0.707,0.707,0,0)", testRotation(0, 0, 45)},
+		{"matrix(0 1 1 0 0 0)", testReflection(0, 0, 1, 1)},
+		{"translate(10 0) rotate(90)", transform.Compose(
+			testRotation(0, 0, 90),
+			testTranslation(10, 0),
+		)},
+		{`transform="rotate(90)"`, testRotation(0, 0, 90)},
+		{`  transform = 'translate(1 2)'  `, testTranslation(1, 2)},
+	}
+	for _, c := range cases {
+		got, err := SVG(c.x)
+		if err != nil {
+			t.Errorf("%q gives %v", c.x, err)
+			continue
+		}
+		checkMatrix(t, c.x, c.want, got)
+	}
+}
+
+// TestSVGBad checks bad SVG transform-strings give *Errors at the problem.
+func TestSVGBad(t *testing.T) {
+	cases := []struct {
+		x            string
+		err          error
+		line, column int
+	}{
+		{"translate(1 2", ErrBadSVG, 1, 14},
+		{"spin(90)", ErrBadSVG, 1, 1},
+		{"rotate(90 1)", ErrBadSVG, 1, 1},
+		{"rotate(90deg)", ErrBadSVG, 1, 8},
+		{"translate(1) scale(2)", transform.ErrNotIsometry, 1, 14},
+		{"matrix(0.71 0.71 -0.71 0.71 0 0)", transform.ErrNotIsometry, 1, 1},
+		{"skewX(10)", transform.ErrNotIsometry, 1, 1},
+		{`transform="rotate(90)`, ErrBadSVG, 1, 22},
+		{"rotate(90) ?", ErrBadSVG, 1, 12},
+	}
+	for _, c := range cases {
+		_, err := SVG(c.x)
+		checkAt(t, c.x, err, c.err, c.line, c.column)
+	}
+}
+
+// TestSVGWithin checks a geometry.Tolerance looser than 1e-3 decides if a
+// function is an isometry.
+func TestSVGWithin(t *testing.T) {
+	x := "matrix(0.71 0.71 -0.71 0.71 0 0)"
+	got, err := SVGWithin(x, geometry.Tolerance{Absolute: 0.1})
+	if err != nil {
+		t.Fatalf("%q gives %v", x, err)
+	}
+	checkMatrix(t, x, testRotation(0, 0, 45), got)
+}
+
+// TestCSS checks CSS transform-strings are parsed into the
+// transform.Transformations they describe.
+func TestCSS(t *testing.T) {
+	cases := []struct {
+		x    string
+		want transform.Transformation
+	}{
+		{"none", transform.NoTransformation()},
+		{"translate(10px, 20px)", testTranslation(10, 20)},
+		{"translateY(5px)", testTranslation(0, 5)},
+		{"rotate(0.25turn)", testRotation(0, 0, 90)},
+		{"rotate(100grad)", testRotation(0, 0, 90)},
+		{"rotate(0)", transform.NoTransformation()},
+		{"scaleX(-100%)", testReflection(0, 0, 0, 1)},
+		{"rotate(90deg) translate(10px,20px)", transform.Compose(
+			testTranslation(10, 20),
+			testRotation(0, 0, 90),
+		)},
+		{"transform: rotate(90deg);", testRotation(0, 0, 90)},
+	}
+	for _, c := range cases {
+		got, err := CSS(c.x)
+		if err != nil {
+			t.Errorf("%q gives %v", c.x, err)
+			continue
+		}
+		checkMatrix(t, c.x, c.want, got)
+	}
+}
+
+// TestCSSBad checks bad CSS transform-strings give *Errors at the problem.
+func TestCSSBad(t *testing.T) {
+	cases := []struct {
+		x            string
+		err          error
+		line, column int
+	}{
+		{"translate(10em)", ErrBadCSS, 1, 11},
+		{"rotate(90)", ErrBadCSS, 1, 8},
+		{"rotate(90 deg)", ErrBadCSS, 1, 11},
+		{"scale(50%)", transform.ErrNotIsometry, 1, 1},
+		{"perspective(10px)", ErrBadCSS, 1, 1},
+	}
+	for _, c := range cases {
+		_, err := CSS(c.x)
+		checkAt(t, c.x, err, c.err, c.line, c.column)
+	}
+}
+
+// TestFormatSVG checks transform.Transformations are written as SVG
+// transform-strings without float noise.
+func TestFormatSVG(t *testing.T) {
+	cases := []struct {
+		x, want string
+	}{
+		{"translate(0)", "translate(0 0)"},
+		{"translate(10 20)", "translate(10 20)"},
+		{"rotate(90 1 2)", "rotate(90 1 2)"},
+		{"rotate(45 5 5)", "rotate(45 5 5)"},
+		{"rotate(-30)", "rotate(-30)"},
+		{"rotate(180)", "rotate(180)"},
+		{"rotate(0.05)", "rotate(0.05)"},
+		{"scale(-1 1)", "scale(-1 1)"},
+		{"scale(1 -1)", "scale(1 -1)"},
+		{"translate(3 4) scale(-1 1)", "translate(3 4) scale(-1 1)"},
+		{"rotate(30) scale(1 -1)", "rotate(30) scale(1 -1)"},
+		{"matrix(0 1 1 0 0 0)", "rotate(90) scale(1 -1)"},
+		{"matrix(0.7071 0.7071 -0.7071 0.7071 0 0)", "rotate(45)"},
+		{"rotate(90) translate(10 20) rotate(-90)", "translate(-20 10)"},
+	}
+	for _, c := range cases {
+		checkFormat(t, c.x, c.want, SVG, FormatSVG)
+	}
+}
+
+// TestFormatCSS checks transform.Transformations are written as CSS
+// transform-strings without float noise.
+func TestFormatCSS(t *testing.T) {
+	cases := []struct {
+		x, want string
+	}{
+		{"none", "none"},
+		{"translate(10px, 20px)", "translate(10px, 20px)"},
+		{"rotate(90deg)", "rotate(90deg)"},
+		{
+			"rotate(90deg) translate(10px,20px)",
+			"translate(-15px, -5px) rotate(90deg) translate(15px, 5px)",
+		},
+		{"scale(-1, 1)", "scale(-1, 1)"},
+		{
+			"translate(1px, 2px) rotate(30deg) scaleY(-1)",
+			"translate(1px, 2px) rotate(30deg) scale(1, -1)",
+		},
+	}
+	for _, c := range cases {
+		checkFormat(t, c.x, c.want, CSS, FormatCSS)
+	}
+}
+
+// checkFormat fails the test if x parsed by parse and written by format isn't
+// want and if want doesn't parse back to the same transform.Transformation.
+func checkFormat(
+	t *testing.T,
+	x, want string,
+	parse func(string) (transform.Transformation, error),
+	format func(w io.Writer, t transform.Transformation) error,
+) {
+	t.Helper()
+	tr, err := parse(x)
+	if err != nil {
+		t.Fatalf("%q gives %v", x, err)
+	}
+	var b strings.Builder
+	if err := format(&b, tr); err != nil {
+		t.Fatalf("%q gives %v", x, err)
+	}
+	if got := b.String(); got != want {
+		t.Errorf("%q is written as %q but should be %q", x, got, want)
+	}
+	back, err := parse(b.String())
+	if err != nil {
+		t.Fatalf("%q gives %v", b.String(), err)
+	}
+	checkMatrix(t, x, tr, back)
+}
+
+// checkMatrix fails the test if transform.Transformation got parsed from x
+// doesn't map geometry.Points like want.
+func checkMatrix(t *testing.T, x string, want, got transform.Transformation) {
+	t.Helper()
+	wm, gm := transform.ToMatrix(want), transform.ToMatrix(got)
+	for i := range wm {
+		for j := range wm[i] {
+			if math.Abs(float64(wm[i][j]-gm[i][j])) > 1e-9 {
+				t.Errorf("%q gives %v but should give %v", x, got, want)
+				return
+			}
+		}
+	}
+}
+
+// testTranslation returns the transform.Transformation translating by <i j>.
+func testTranslation(i, j geometry.Number) transform.Transformation {
+	return transform.Translation(geometry.Vector{I: i, J: j})
+}
+
+// testRotation returns the transform.Transformation rotating by degrees around
+// (x y).
+func testRotation(x, y, degrees geometry.Number) transform.Transformation {
+	return transform.Rotation(
+		geometry.Point{X: x, Y: y},
+		geometry.Angle(degrees*math.Pi/180),
+	)
+}
+
+// testReflection returns the transform.Transformation reflecting across the
+// geometry.Line through (ax ay) and (bx by).
+func testReflection(ax, ay, bx, by geometry.Number) transform.Transformation {
+	return transform.LineReflection(geometry.MustLine(geometry.NewLineFromPoints(
+		geometry.Point{X: ax, Y: ay},
+		geometry.Point{X: bx, Y: by},
+	)))
+}
@@ -0,0 +1,203 @@
+package parse
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Position of a token in the string being parsed.
+//
+// Lines and columns start at 1 and columns count characters.
+type Position struct {
+	Line, Column int
+}
+
+// String-representation of the Position.
+//
+// Looks like 'Line:Column'.
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Kinds of tokens.
+const (
+	// tokenEOF ends every list of tokens.
+	tokenEOF tokenKind = iota
+	// tokenNewline separates top-level expressions.
+	tokenNewline
+	// tokenComment is a '#' and everything after it on the same line.
+	tokenComment
+	// tokenIdent is a name like 'Rotation'.
+	tokenIdent
+	// tokenNumber is an unsigned number like '1.5e-3'.
+	tokenNumber
+	// tokenOpen is one of '(', '<', or '{'.
+	tokenOpen
+	// tokenClose is one of ')', '>', or '}'.
+	tokenClose
+	// tokenComma separates arguments.
+	tokenComma
+	// tokenOperator is one of '+', '-', '*', or '/'.
+	tokenOperator
+	// tokenAssign is the '=' in a definition.
+	tokenAssign
+	// tokenString is a double-quoted string like '"other.txt"'.
+	tokenString
+	// tokenIllegal is a character that can't start any other token.
+	tokenIllegal
+	// tokenSpace is spaces, tabs, and carriage-returns which are dropped.
+	tokenSpace
+)
+
+// tokenKind is the kind of a token.
+type tokenKind int
+
+// token is a piece of the string being parsed along with where it starts.
+type token struct {
+	kind tokenKind
+	text string
+	pos  Position
+	// spaceBefore is true if the token starts the string or comes after
+	// whitespace or a comment.
+	spaceBefore bool
+}
+
+// tokenize the string x.
+//
+// Spaces, tabs, and carriage-returns only separate tokens and every other
+// character belongs to a token. The last token is always a tokenEOF.
+func tokenize(x string) []token {
+	var ts []token
+	pos := Position{Line: 1, Column: 1}
+	space := true
+	for len(x) > 0 {
+		r, size := utf8.DecodeRuneInString(x)
+		n := size
+		kind := tokenIllegal
+		switch {
+		case r == ' ' || r == '\t' || r == '\r':
+			kind = tokenSpace
+		case r == '\n':
+			kind = tokenNewline
+		case r == '#':
+			kind = tokenComment
+			n = strings.IndexByte(x, '\n')
+			if n == -1 {
+				n = len(x)
+			}
+		case r == '(' || r == '<' || r == '{':
+			kind = tokenOpen
+		case r == ')' || r == '>' || r == '}':
+			kind = tokenClose
+		case r == ',':
+			kind = tokenComma
+		case r == '+' || r == '-' || r == '*' || r == '/':
+			kind = tokenOperator
+		case r == '=':
+			kind = tokenAssign
+		case r == '"':
+			kind, n = tokenString, stringLength(x)
+		case isNumberStart(x):
+			kind, n = tokenNumber, numberLength(x)
+		case r == '_' || unicode.IsLetter(r):
+			kind, n = tokenIdent, identLength(x)
+		}
+		if kind != tokenSpace {
+			ts = append(ts, token{
+				kind:        kind,
+				text:        x[:n],
+				pos:         pos,
+				spaceBefore: space,
+			})
+		}
+		space = kind == tokenSpace || kind == tokenNewline ||
+			kind == tokenComment
+		if kind == tokenNewline {
+			pos = Position{Line: pos.Line + 1, Column: 1}
+		} else {
+			pos.Column += utf8.RuneCountInString(x[:n])
+		}
+		x = x[n:]
+	}
+	return append(ts, token{kind: tokenEOF, pos: pos, spaceBefore: true})
+}
+
+// isNumberStart returns true if the string x starts with a number.
+//
+// Numbers start with a digit or a '.' followed by a digit. Signs are
+// operators.
+func isNumberStart(x string) bool {
+	if x[0] == '.' {
+		x = x[1:]
+	}
+	return len(x) > 0 && isDigit(x[0])
+}
+
+// numberLength returns the length of the number at the start of the string x.
+//
+// The number is everything that can belong to a decimal or exponent since
+// whether the number is good is decided when it is parsed. A letter other than
+// an exponent's 'e' ends the number so units like '90deg' can follow it.
+func numberLength(x string) int {
+	n := 1
+	for n < len(x) {
+		c := x[n]
+		switch {
+		case isDigit(c) || c == '.':
+		case (c == 'e' || c == 'E') && isExponent(x[n+1:]):
+		case (c == '+' || c == '-') && (x[n-1] == 'e' || x[n-1] == 'E'):
+		default:
+			return n
+		}
+		n++
+	}
+	return n
+}
+
+// isExponent returns true if the string x after an 'e' is the rest of an
+// exponent which is an optional sign followed by a digit.
+func isExponent(x string) bool {
+	if len(x) > 0 && (x[0] == '+' || x[0] == '-') {
+		x = x[1:]
+	}
+	return len(x) > 0 && isDigit(x[0])
+}
+
+// stringLength returns the length of the double-quoted string at the start of
+// the string x.
+//
+// The string ends at the first '"' that isn't escaped by a '\' or at the end of
+// the line if there isn't one so unterminated strings are left for parsing to
+// report.
+func stringLength(x string) int {
+	for n := 1; n < len(x); n++ {
+		switch x[n] {
+		case '\\':
+			if n+1 < len(x) && x[n+1] != '\n' {
+				n++
+			}
+		case '"':
+			return n + 1
+		case '\n':
+			return n
+		}
+	}
+	return len(x)
+}
+
+// identLength returns the length of the name at the start of the string x.
+func identLength(x string) int {
+	for n, r := range x {
+		if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return n
+		}
+	}
+	return len(x)
+}
+
+// isDigit returns true if c is a decimal digit.
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
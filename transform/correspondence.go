@@ -0,0 +1,83 @@
+package transform
+
+import (
+	"errors"
+
+	"github.com/jwowillo/viztransform/geometry"
+)
+
+// ErrInconsistentDistances is returned when geometry.Points can't be mapped to
+// other geometry.Points by a Transformation because the distances between them
+// change.
+var ErrInconsistentDistances = errors.New(
+	"distances between geometry.Points aren't preserved",
+)
+
+// FromCorrespondence returns the Transformation that maps geometry.Point a to
+// a2, b to b2, and c to c2.
+//
+// The Transformation is built from at most 3 line-reflections. The first
+// reflects across the perpendicular bisector of a and a2 to move a to a2. The
+// second reflects across the perpendicular bisector of where b was moved and
+// b2, which passes through a2 so a2 stays in place. The third does the same
+// for c and passes through both a2 and b2. Any line-reflection that isn't
+// needed because its geometry.Point is already in place is skipped. The
+// Transformation isn't simplified.
+//
+// The Transformation is unique if a, b, and c aren't on the same
+// geometry.Line. Otherwise, the one with the fewest line-reflections is
+// returned.
+//
+// Returns ErrInconsistentDistances if the distance between any two of a, b, and
+// c isn't the same as the distance between the corresponding two of a2, b2, and
+// c2.
+func FromCorrespondence(a, a2, b, b2, c, c2 geometry.Point) (
+	Transformation,
+	error,
+) {
+	return FromCorrespondenceWithin(
+		a, a2, b, b2, c, c2,
+		geometry.DefaultTolerance(),
+	)
+}
+
+// FromCorrespondenceWithin is FromCorrespondence where distances and
+// geometry.Points are compared within geometry.Tolerance tol.
+//
+// Useful for geometry.Points that were measured and so only keep distances
+// roughly the same.
+func FromCorrespondenceWithin(
+	a, a2, b, b2, c, c2 geometry.Point,
+	tol geometry.Tolerance,
+) (Transformation, error) {
+	if !tol.AreEqual(geometry.Distance(a, b), geometry.Distance(a2, b2)) ||
+		!tol.AreEqual(geometry.Distance(a, c), geometry.Distance(a2, c2)) ||
+		!tol.AreEqual(geometry.Distance(b, c), geometry.Distance(b2, c2)) {
+		return nil, ErrInconsistentDistances
+	}
+	t := NoTransformation()
+	for _, pair := range [][2]geometry.Point{{a, a2}, {b, b2}, {c, c2}} {
+		from, to := Apply(t, pair[0]), pair[1]
+		if tol.AreSamePoint(from, to) {
+			continue
+		}
+		l := perpendicularBisector(from, to, tol)
+		t = Compose(t, LineReflection(l))
+	}
+	return t, nil
+}
+
+// perpendicularBisector returns the geometry.Line that geometry.Points a and b
+// are reflections of each other across.
+//
+// a and b must be different within geometry.Tolerance tol.
+func perpendicularBisector(
+	a, b geometry.Point,
+	tol geometry.Tolerance,
+) geometry.Line {
+	l := geometry.MustLine(tol.NewLineFromPoints(a, b))
+	return geometry.PerpendicularThroughPoint(
+		l,
+		geometry.Point{X: (a.X + b.X) / 2, Y: (a.Y + b.Y) / 2},
+	)
+}
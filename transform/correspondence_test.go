@@ -0,0 +1,99 @@
+package transform
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jwowillo/viztransform/geometry"
+)
+
+// TestFromCorrespondence checks the Transformation built from where 3
+// geometry.Points go maps them there and is the Transformation they came from
+// when they aren't on the same geometry.Line.
+func TestFromCorrespondence(t *testing.T) {
+	ts := []Transformation{
+		NoTransformation(),
+		Translation(geometry.Vector{I: 3, J: -2}),
+		Rotation(geometry.Point{X: 1, Y: 2}, math.Pi/3),
+		LineReflection(testLine(0, 1, 1, 3)),
+		GlideReflection(testLine(-1, 0, 1, 1), geometry.Vector{I: 2, J: 1}),
+	}
+	// compare is true if the result is the Transformation the geometry.Points
+	// came from, which needs them to not be on the same geometry.Line, and is
+	// close enough to testPoints to compare there.
+	cases := []struct {
+		a, b, c geometry.Point
+		compare bool
+	}{
+		{geometry.Point{X: 0, Y: 0}, geometry.Point{X: 1, Y: 0},
+			geometry.Point{X: 0, Y: 1}, true},
+		{geometry.Point{X: 1e6, Y: 1e6}, geometry.Point{X: 1e6 + 3, Y: 1e6},
+			geometry.Point{X: 1e6, Y: 1e6 + 4}, false},
+		{geometry.Point{X: 0, Y: 0}, geometry.Point{X: 1, Y: 1},
+			geometry.Point{X: 2, Y: 2}, false},
+		{geometry.Point{X: 2, Y: 5}, geometry.Point{X: 2, Y: 5},
+			geometry.Point{X: 2, Y: 5}, false},
+	}
+	for _, tr := range ts {
+		for _, c := range cases {
+			a2, b2, c2 := Apply(tr, c.a), Apply(tr, c.b), Apply(tr, c.c)
+			got, err := FromCorrespondence(c.a, a2, c.b, b2, c.c, c2)
+			if err != nil {
+				t.Fatalf("%v moving %v, %v, and %v gives %v",
+					tr, c.a, c.b, c.c, err)
+			}
+			pairs := [][2]geometry.Point{{c.a, a2}, {c.b, b2}, {c.c, c2}}
+			for _, p := range pairs {
+				if q := Apply(got, p[0]); !geometry.AreSamePoint(q, p[1]) {
+					t.Errorf("%v maps %v to %v but should map it to %v",
+						got, p[0], q, p[1])
+				}
+			}
+			if c.compare {
+				checkSame(t, tr, got)
+			}
+		}
+	}
+}
+
+// TestFromCorrespondenceWithin checks geometry.Points that don't keep their
+// distances within the geometry.Tolerance give ErrInconsistentDistances.
+func TestFromCorrespondenceWithin(t *testing.T) {
+	a, b, c := geometry.Point{X: 0, Y: 0}, geometry.Point{X: 1, Y: 0},
+		geometry.Point{X: 0, Y: 1}
+	loose := geometry.Tolerance{Absolute: 1e-2}
+	tight := geometry.Tolerance{Absolute: 1e-12}
+	cases := []struct {
+		a2, b2, c2 geometry.Point
+		tol        geometry.Tolerance
+		want       error
+	}{
+		{geometry.Point{X: 0, Y: 0}, geometry.Point{X: 2, Y: 0},
+			geometry.Point{X: 0, Y: 1}, geometry.DefaultTolerance(),
+			ErrInconsistentDistances},
+		{geometry.Point{X: 5, Y: 5}, geometry.Point{X: 5, Y: 5},
+			geometry.Point{X: 5, Y: 5}, loose, ErrInconsistentDistances},
+		{geometry.Point{X: 0, Y: 0}, geometry.Point{X: 1.001, Y: 0},
+			geometry.Point{X: 0, Y: 1}, geometry.DefaultTolerance(),
+			ErrInconsistentDistances},
+		{geometry.Point{X: 0, Y: 0}, geometry.Point{X: 1.001, Y: 0},
+			geometry.Point{X: 0, Y: 1}, loose, nil},
+		{geometry.Point{X: 1e-9, Y: 0}, geometry.Point{X: 1 + 1e-9, Y: 0},
+			geometry.Point{X: 1e-9, Y: 1}, tight, nil},
+	}
+	for _, tc := range cases {
+		got, err := FromCorrespondenceWithin(
+			a, tc.a2, b, tc.b2, c, tc.c2,
+			tc.tol,
+		)
+		if err != tc.want {
+			t.Errorf("moving %v, %v, and %v to %v, %v, and %v gives %v but "+
+				"should give %v", a, b, c, tc.a2, tc.b2, tc.c2, err, tc.want)
+		}
+		if q := Apply(got, a); err == nil && tc.tol == tight &&
+			!tight.AreSamePoint(q, tc.a2) {
+			t.Errorf("%v maps %v to %v but should map it to %v",
+				got, a, q, tc.a2)
+		}
+	}
+}
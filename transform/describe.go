@@ -0,0 +1,229 @@
+package transform
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/jwowillo/viztransform/geometry"
+)
+
+// Params are the values that define a Transformation of a Type.
+//
+// Params is one of NoTransformationParams, LineReflectionParams,
+// TranslationParams, RotationParams, or GlideParams. Each takes the same
+// arguments as the corresponding Transformation-constructor.
+type Params interface {
+	// Type of the Transformation the Params define.
+	Type() Type
+	// String-representation of the Transformation the Params define.
+	//
+	// Looks like a called Transformation-constructor with arguments in
+	// their respective string-representations.
+	String() string
+}
+
+// NoTransformationParams define a Transformation with TypeNoTransformation.
+type NoTransformationParams struct{}
+
+// Type returns TypeNoTransformation.
+func (NoTransformationParams) Type() Type {
+	return TypeNoTransformation
+}
+
+// String-representation of the Transformation.
+//
+// Looks like 'NoTransformation()'.
+func (NoTransformationParams) String() string {
+	return "NoTransformation()"
+}
+
+// LineReflectionParams define a Transformation with TypeLineReflection across
+// geometry.Line Line.
+type LineReflectionParams struct {
+	Line geometry.Line
+}
+
+// Type returns TypeLineReflection.
+func (LineReflectionParams) Type() Type {
+	return TypeLineReflection
+}
+
+// String-representation of the Transformation.
+//
+// Looks like 'LineReflection(geometry.Line)' where geometry.Line is the
+// geometry.Line reflected across.
+func (p LineReflectionParams) String() string {
+	return fmt.Sprintf("LineReflection(%s)", p.Line)
+}
+
+// TranslationParams define a Transformation with TypeTranslation by
+// geometry.Vector Vector.
+type TranslationParams struct {
+	Vector geometry.Vector
+}
+
+// Type returns TypeTranslation.
+func (TranslationParams) Type() Type {
+	return TypeTranslation
+}
+
+// String-representation of the Transformation.
+//
+// Looks like 'Translation(geometry.Vector)' where geometry.Vector is the
+// geometry.Vector translated by.
+func (p TranslationParams) String() string {
+	return fmt.Sprintf("Translation(%s)", p.Vector)
+}
+
+// RotationParams define a Transformation with TypeRotation counter-clockwise
+// by geometry.Angle Angle around geometry.Point Center.
+type RotationParams struct {
+	Center geometry.Point
+	// Angle is in (-pi, pi] when the RotationParams are from Describe.
+	Angle geometry.Angle
+}
+
+// Type returns TypeRotation.
+func (RotationParams) Type() Type {
+	return TypeRotation
+}
+
+// String-representation of the Transformation.
+//
+// Looks like 'Rotation(geometry.Point, geometry.Angle)' where geometry.Point
+// is the center and geometry.Angle is the angle rotated by mod 2pi.
+func (p RotationParams) String() string {
+	return fmt.Sprintf("Rotation(%s, %s)", p.Center, p.Angle)
+}
+
+// StringIn returns the String-representation of the Transformation Params p
+// define with geometry.Angles in geometry.AngleUnit u.
+func StringIn(p Params, u geometry.AngleUnit) string {
+	if r, ok := p.(RotationParams); ok {
+		return fmt.Sprintf("Rotation(%s, %s)", r.Center, r.Angle.In(u))
+	}
+	return p.String()
+}
+
+// GlideParams define a Transformation with TypeGlideReflection across
+// geometry.Line Axis and by geometry.Vector Vector which is parallel to Axis.
+type GlideParams struct {
+	Axis   geometry.Line
+	Vector geometry.Vector
+}
+
+// Type returns TypeGlideReflection.
+func (GlideParams) Type() Type {
+	return TypeGlideReflection
+}
+
+// String-representation of the Transformation.
+//
+// Looks like 'GlideReflection(geometry.Line, geometry.Vector)' where
+// geometry.Line is the geometry.Line the reflection is happening over and
+// geometry.Vector is the geometry.Vector of translation.
+func (p GlideParams) String() string {
+	return fmt.Sprintf("GlideReflection(%s, %s)", p.Axis, p.Vector)
+}
+
+// Describe Transformation t by the Params of its simplified form.
+//
+// The Params are the same values shown by t's string-representation.
+func Describe(t Transformation) Params {
+	return DescribeWithin(t, geometry.DefaultTolerance())
+}
+
+// DescribeWithin is Describe where geometry.Lines are compared within
+// geometry.Tolerance tol.
+//
+// Panics with the InstabilityError DescribeWithinE would return if simplifying
+// or describing t fails.
+func DescribeWithin(t Transformation, tol geometry.Tolerance) Params {
+	p, err := DescribeWithinE(t, tol)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// DescribeE is Describe that returns an error instead of panicking.
+//
+// Returns an InstabilityError if SimplifyE does or if the geometry.Lines of a
+// rotation don't intersect.
+func DescribeE(t Transformation) (Params, error) {
+	return DescribeWithinE(t, geometry.DefaultTolerance())
+}
+
+// DescribeWithinE is DescribeE where geometry.Lines are compared within
+// geometry.Tolerance tol.
+func DescribeWithinE(t Transformation, tol geometry.Tolerance) (Params, error) {
+	t, err := SimplifyWithinE(t, tol)
+	if err != nil {
+		return nil, err
+	}
+	switch typeOf(t, tol) {
+	case TypeLineReflection:
+		return LineReflectionParams{Line: t[0]}, nil
+	case TypeTranslation:
+		return describeTranslation(t[0], t[1], tol), nil
+	case TypeRotation:
+		return describeRotation(t[0], t[1], tol)
+	case TypeGlideReflection:
+		return describeGlideReflection(t[0], t[1], t[2], tol), nil
+	}
+	return NoTransformationParams{}, nil
+}
+
+// describeTranslation returns the TranslationParams of the Transformation
+// created by parallel geometry.Lines a and b.
+//
+// The geometry.Vector is the shortest geometry.Vector from a to b scaled by 2
+// since a translation from 2 line-reflections translates by 2 times the
+// shortest distance from the first geometry.Line to the second.
+func describeTranslation(
+	a, b geometry.Line,
+	tol geometry.Tolerance,
+) TranslationParams {
+	v := tol.ShortestVector(a, b)
+	return TranslationParams{Vector: geometry.Vector{I: 2 * v.I, J: 2 * v.J}}
+}
+
+// describeRotation returns the RotationParams of the Transformation created by
+// intersecting geometry.Lines a and b.
+//
+// Returns an InstabilityError if a and b don't intersect within tol.
+//
+// The center is the intersection of a and b and the geometry.Angle is 2 times
+// the angle from a to b since a rotation from 2 line-reflections rotates 2
+// times the angle from the first geometry.Line to the second. The
+// geometry.Angle is turned into the equivalent one in (-pi, pi].
+func describeRotation(
+	a, b geometry.Line,
+	tol geometry.Tolerance,
+) (RotationParams, error) {
+	rads := math.Remainder(2*float64(tol.AngleBetween(a, b)), 2*math.Pi)
+	if rads == -math.Pi {
+		rads = math.Pi
+	}
+	center, err := tol.Intersection(a, b)
+	if err != nil {
+		return RotationParams{}, unstable(err, a, b)
+	}
+	return RotationParams{Center: center, Angle: geometry.Angle(rads)}, nil
+}
+
+// describeGlideReflection returns the GlideParams of the Transformation created
+// by geometry.Lines a, b, and c with a and b parallel and b and c
+// perpendicular or a and b perpendicular and b and c parallel.
+//
+// The axis is the geometry.Line perpendicular to the others and the
+// geometry.Vector is the one the parallel geometry.Lines translate by.
+func describeGlideReflection(
+	a, b, c geometry.Line,
+	tol geometry.Tolerance,
+) GlideParams {
+	if tol.AreParallel(b, c) {
+		a, b, c = b, c, a
+	}
+	return GlideParams{Axis: c, Vector: describeTranslation(a, b, tol).Vector}
+}
@@ -0,0 +1,48 @@
+package transform
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/jwowillo/viztransform/geometry"
+)
+
+// TestDescribeRotationAngle checks Describe gives rotation angles in
+// (-pi, pi] and that the JSON has the same angle.
+func TestDescribeRotationAngle(t *testing.T) {
+	cases := []struct {
+		rads, want float64
+	}{
+		{math.Pi / 2, math.Pi / 2},
+		{-math.Pi / 2, -math.Pi / 2},
+		{3 * math.Pi / 2, -math.Pi / 2},
+		{-3 * math.Pi / 2, math.Pi / 2},
+		{math.Pi, math.Pi},
+		{-math.Pi, math.Pi},
+		{1, 1},
+		{-4, 2*math.Pi - 4},
+	}
+	c := geometry.Point{X: 1, Y: 2}
+	for _, tc := range cases {
+		p, ok := Describe(Rotation(c, geometry.Angle(tc.rads))).(RotationParams)
+		if !ok {
+			t.Fatalf("rotation by %v isn't described as a rotation", tc.rads)
+		}
+		if math.Abs(float64(p.Angle)-tc.want) > 1e-9 {
+			t.Errorf("rotation by %v has angle %v but should be %v",
+				tc.rads, float64(p.Angle), tc.want)
+		}
+		bs, err := json.Marshal(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var v struct{ Angle float64 }
+		if err := json.Unmarshal(bs, &v); err != nil {
+			t.Fatal(err)
+		}
+		if v.Angle != float64(p.Angle) {
+			t.Errorf("JSON angle %v isn't Describe's %v", v.Angle, p.Angle)
+		}
+	}
+}
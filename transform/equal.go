@@ -0,0 +1,77 @@
+package transform
+
+import (
+	"math"
+
+	"github.com/jwowillo/viztransform/geometry"
+)
+
+// Equal returns true if Transformations a and b map every geometry.Point the
+// same way even if they're made of different geometry.Lines.
+//
+// The Transformations are compared by their Matrices with every entry being
+// equal as described by geometry.AreEqual.
+func Equal(a, b Transformation) bool {
+	return EqualWithin(a, b, geometry.DefaultTolerance())
+}
+
+// EqualWithin is Equal where the Matrix-entries are compared within
+// geometry.Tolerance tol.
+func EqualWithin(a, b Transformation, tol geometry.Tolerance) bool {
+	ma, mb := ToMatrix(a), ToMatrix(b)
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 3; j++ {
+			if !tol.AreEqual(ma[i][j], mb[i][j]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Key is a comparable value that is the same for Transformations that map
+// every geometry.Point the same way so it can be used as a map-key.
+type Key [6]int64
+
+// Key of the Transformation where Matrix-entries within tol of each other are
+// treated as the same.
+//
+// The Key is the top 2 rows of the Transformation's Matrix with every entry
+// rounded to the nearest multiple of tol. Transformations with Matrix-entries
+// closer than tol can still have different Keys if an entry rounds to
+// different multiples so tol should be much larger than the differences
+// expected from rounding and much smaller than the differences between
+// Transformations that should be different.
+//
+// Entries too large to round to an int64 are clamped to the closest int64 so
+// Transformations with entries that large can share a Key.
+//
+// geometry.Epsilon is used if tol isn't positive.
+func (t Transformation) Key(tol geometry.Number) Key {
+	if !(tol > 0) {
+		tol = geometry.Epsilon
+	}
+	m := ToMatrix(t)
+	var k Key
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 3; j++ {
+			k[3*i+j] = clamp(math.Round(float64(m[i][j] / tol)))
+		}
+	}
+	return k
+}
+
+// clamp returns the int64 closest to x or 0 if x is NaN.
+//
+// Converting a float64 outside the range of int64 directly isn't defined.
+func clamp(x float64) int64 {
+	switch {
+	case math.IsNaN(x):
+		return 0
+	case x >= math.MaxInt64:
+		return math.MaxInt64
+	case x <= math.MinInt64:
+		return math.MinInt64
+	}
+	return int64(x)
+}
@@ -0,0 +1,47 @@
+package transform
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jwowillo/viztransform/geometry"
+)
+
+// TestKeyLargeEntries checks Keys of Transformations with Matrix-entries too
+// large for an int64 once divided by the tolerance are clamped.
+func TestKeyLargeEntries(t *testing.T) {
+	cases := []struct {
+		v    geometry.Vector
+		want int64
+	}{
+		{geometry.Vector{I: 1e13, J: 0}, math.MaxInt64},
+		{geometry.Vector{I: -1e13, J: 0}, math.MinInt64},
+		{geometry.Vector{I: 1e300, J: 0}, math.MaxInt64},
+	}
+	for _, c := range cases {
+		k := Translation(c.v).Key(1e-7)
+		if k[2] != c.want {
+			t.Errorf("translation by %v has Key-entry %d but should be %d",
+				c.v, k[2], c.want)
+		}
+	}
+	a := Translation(geometry.Vector{I: 1, J: 2}).Key(1e-7)
+	b := Translation(geometry.Vector{I: 1 + 1e-9, J: 2}).Key(1e-7)
+	if a != b {
+		t.Errorf("nearly the same translations have Keys %v and %v", a, b)
+	}
+}
+
+// TestEqualWithin checks Transformations are only equal within a
+// geometry.Tolerance large enough.
+func TestEqualWithin(t *testing.T) {
+	a := Translation(geometry.Vector{I: 1, J: 0})
+	b := Translation(geometry.Vector{I: 1.001, J: 0})
+	if Equal(a, b) {
+		t.Errorf("%v and %v are Equal", a, b)
+	}
+	loose := geometry.Tolerance{Absolute: 0.01, Relative: 0.01}
+	if !EqualWithin(a, b, loose) {
+		t.Errorf("%v and %v aren't EqualWithin %v", a, b, loose)
+	}
+}
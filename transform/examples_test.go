@@ -0,0 +1,41 @@
+package transform_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jwowillo/viztransform/parse"
+	"github.com/jwowillo/viztransform/transform"
+)
+
+// TestSimplifyParallelExamples checks SimplifyParallel expresses the same
+// Transformation as Simplify for every example.
+func TestSimplifyParallelExamples(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("..", "example", "*.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no examples")
+	}
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			f, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			tr, err := parse.Transformation(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := transform.Simplify(tr)
+			got := transform.SimplifyParallel(tr)
+			if !transform.Equal(got, want) {
+				t.Errorf("simplifies to %v but should simplify to %v",
+					got, want)
+			}
+		})
+	}
+}
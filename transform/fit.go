@@ -0,0 +1,105 @@
+package transform
+
+import (
+	"errors"
+	"math"
+
+	"github.com/jwowillo/viztransform/geometry"
+)
+
+// ErrMismatchedPoints is returned when lists of geometry.Points that should
+// correspond to each other don't.
+var ErrMismatchedPoints = errors.New(
+	"geometry.Point-lists must be non-empty and the same length",
+)
+
+// Fit returns the Transformation that best maps each geometry.Point in src to
+// the geometry.Point at the same index in dst in least squares along with the
+// residual.
+//
+// Both Transformations that keep the orientation of geometry.Points and ones
+// that reverse it are considered. The residual is the root-mean-square
+// distance between where the Transformation maps each geometry.Point in src
+// and the corresponding geometry.Point in dst. The residual is 0 when dst is
+// exactly src transformed. The Transformation that keeps the orientation is
+// returned if both have the same residual.
+//
+// Returns ErrMismatchedPoints if src and dst are empty or aren't the same
+// length.
+func Fit(
+	src, dst []geometry.Point,
+) (Transformation, geometry.Number, error) {
+	return FitWithin(src, dst, geometry.DefaultTolerance())
+}
+
+// FitWithin is Fit where residuals and the fit Matrix's entries are compared
+// within geometry.Tolerance tol.
+func FitWithin(
+	src, dst []geometry.Point,
+	tol geometry.Tolerance,
+) (Transformation, geometry.Number, error) {
+	if len(src) == 0 || len(src) != len(dst) {
+		return nil, 0, ErrMismatchedPoints
+	}
+	cs, cd := centroid(src), centroid(dst)
+	// dot, cross, and flip are the sums that the best angles for the proper
+	// and improper Matrices maximize.
+	var dot, cross, flipCos, flipSin geometry.Number
+	for i := range src {
+		px, py := src[i].X-cs.X, src[i].Y-cs.Y
+		qx, qy := dst[i].X-cd.X, dst[i].Y-cd.Y
+		dot += px*qx + py*qy
+		cross += px*qy - py*qx
+		flipCos += px*qx - py*qy
+		flipSin += py*qx + px*qy
+	}
+	proper := fitMatrix(math.Atan2(float64(cross), float64(dot)), 1, cs, cd)
+	improper := fitMatrix(
+		math.Atan2(float64(flipSin), float64(flipCos)),
+		-1,
+		cs, cd,
+	)
+	m, residual := proper, fitResidual(proper, src, dst)
+	if r := fitResidual(improper, src, dst); r < residual &&
+		!tol.AreEqual(r, residual) {
+		m, residual = improper, r
+	}
+	t, err := FromMatrixWithin(m, tol)
+	if err != nil {
+		return nil, 0, err
+	}
+	return t, residual, nil
+}
+
+// fitMatrix returns the Matrix that maps geometry.Point cs to geometry.Point cd
+// and rotates counter-clockwise by rads around cs if sign is 1 or reflects
+// across the geometry.Line through cs at angle rads/2 if sign is -1.
+func fitMatrix(rads float64, sign geometry.Number, cs, cd geometry.Point) Matrix {
+	cos, sin := geometry.Number(math.Cos(rads)), geometry.Number(math.Sin(rads))
+	m := Matrix{{cos, -sign * sin, 0}, {sin, sign * cos, 0}, {0, 0, 1}}
+	moved := ApplyMatrix(m, cs)
+	m[0][2], m[1][2] = cd.X-moved.X, cd.Y-moved.Y
+	return m
+}
+
+// fitResidual returns the root-mean-square distance between where Matrix m
+// maps each geometry.Point in src and the corresponding geometry.Point in dst.
+func fitResidual(m Matrix, src, dst []geometry.Point) geometry.Number {
+	var sum geometry.Number
+	for i := range src {
+		d := geometry.Distance(ApplyMatrix(m, src[i]), dst[i])
+		sum += d * d
+	}
+	return geometry.Number(math.Sqrt(float64(sum / geometry.Number(len(src)))))
+}
+
+// centroid returns the average of geometry.Points ps.
+func centroid(ps []geometry.Point) geometry.Point {
+	var c geometry.Point
+	for _, p := range ps {
+		c.X += p.X
+		c.Y += p.Y
+	}
+	n := geometry.Number(len(ps))
+	return geometry.Point{X: c.X / n, Y: c.Y / n}
+}
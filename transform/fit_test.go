@@ -0,0 +1,78 @@
+package transform
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jwowillo/viztransform/geometry"
+)
+
+// TestFit checks Fit finds the Transformation that moved known
+// geometry.Points with a residual of 0 and the best one when the
+// geometry.Points are noisy.
+func TestFit(t *testing.T) {
+	cases := []struct {
+		want Transformation
+		kind Type
+	}{
+		{NoTransformation(), TypeNoTransformation},
+		{Translation(geometry.Vector{I: 3, J: -2}), TypeTranslation},
+		{Rotation(geometry.Point{X: 1, Y: 2}, math.Pi/3), TypeRotation},
+		{LineReflection(testLine(0, 1, 1, 3)), TypeLineReflection},
+		{
+			GlideReflection(testLine(-1, 0, 1, 1), geometry.Vector{I: 2, J: 1}),
+			TypeGlideReflection,
+		},
+	}
+	for _, c := range cases {
+		dst := make([]geometry.Point, len(testPoints))
+		for i, p := range testPoints {
+			dst[i] = Apply(c.want, p)
+		}
+		got, residual, err := Fit(testPoints, dst)
+		if err != nil {
+			t.Fatalf("fitting %v gives %v", c.want, err)
+		}
+		if !geometry.IsZero(residual) {
+			t.Errorf("fitting %v has residual %v but should have 0",
+				c.want, residual)
+		}
+		if TypeOf(got) != c.kind {
+			t.Errorf("fitting %v gives %v", c.want, got)
+		}
+		checkSame(t, c.want, got)
+	}
+}
+
+// TestFitNoisy checks Fit averages out noise in the geometry.Points.
+func TestFitNoisy(t *testing.T) {
+	src := []geometry.Point{
+		{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 0, Y: 2}, {X: 2, Y: 2},
+	}
+	dst := []geometry.Point{
+		{X: 1.1, Y: 0}, {X: 2.9, Y: 0}, {X: 1.1, Y: 2}, {X: 2.9, Y: 2},
+	}
+	got, residual, err := Fit(src, dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSame(t, Translation(geometry.Vector{I: 1, J: 0}), got)
+	if math.Abs(float64(residual)-0.1) > 1e-9 {
+		t.Errorf("residual is %v but should be 0.1", residual)
+	}
+}
+
+// TestFitMismatched checks Fit returns ErrMismatchedPoints for lists of
+// geometry.Points that don't correspond.
+func TestFitMismatched(t *testing.T) {
+	cases := [][2][]geometry.Point{
+		{nil, nil},
+		{testPoints, testPoints[1:]},
+	}
+	for _, c := range cases {
+		if _, _, err := Fit(c[0], c[1]); err != ErrMismatchedPoints {
+			t.Errorf("fitting %v to %v gives %v but should give %v",
+				c[0], c[1], err, ErrMismatchedPoints)
+		}
+	}
+}
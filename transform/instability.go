@@ -0,0 +1,46 @@
+package transform
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jwowillo/viztransform/geometry"
+)
+
+// ErrNumericalInstability is returned when geometry.Lines are so close to being
+// degenerate, like being almost parallel within geometry.Epsilon, that
+// rounding makes working with them fail.
+var ErrNumericalInstability = errors.New("geometry.Lines are numerically unstable")
+
+// InstabilityError is the error returned when working with geometry.Lines fails
+// because of ErrNumericalInstability.
+//
+// errors.Is reports the error as ErrNumericalInstability and the geometry error
+// that caused it.
+type InstabilityError struct {
+	// Lines being worked with when the failure happened.
+	Lines []geometry.Line
+	// Err is the geometry error that caused the failure.
+	Err error
+}
+
+// Error describes the failure and the geometry.Lines that caused it.
+func (e *InstabilityError) Error() string {
+	return fmt.Sprintf("%v: %v for %v", ErrNumericalInstability, e.Err, e.Lines)
+}
+
+// Unwrap returns the geometry error that caused the failure.
+func (e *InstabilityError) Unwrap() error {
+	return e.Err
+}
+
+// Is returns true if target is ErrNumericalInstability.
+func (e *InstabilityError) Is(target error) bool {
+	return target == ErrNumericalInstability
+}
+
+// unstable returns an InstabilityError caused by err while working with
+// geometry.Lines ls.
+func unstable(err error, ls ...geometry.Line) error {
+	return &InstabilityError{Lines: ls, Err: err}
+}
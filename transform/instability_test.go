@@ -0,0 +1,86 @@
+package transform
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jwowillo/viztransform/geometry"
+)
+
+// TestInstabilityError checks the simplifier returns InstabilityErrors carrying
+// the geometry.Lines being worked with instead of panicking.
+func TestInstabilityError(t *testing.T) {
+	s := simplifier{tol: geometry.DefaultTolerance()}
+	a, b := testLine(0, 0, 1, 0), testLine(0, 0, 1, 1)
+	c, d := testLine(0, 0, 0, 1), testLine(0, 0, -1, 1)
+	_, _, err := s.rotateBCToSame(a, b, c, d)
+	checkInstability(t, err, geometry.ErrNoLine, 4)
+	p, q := testLine(0, 1, 1, 1), testLine(0, 2, 1, 2)
+	_, _, _, err = s.rotateToParallelAndPerpendicular(a, p, q)
+	checkInstability(t, err, geometry.ErrNoIntersection, 3)
+}
+
+// unstableTransformation is made of 3 almost parallel geometry.Lines that
+// simplify to a Transformation that isn't simplified.
+var unstableTransformation = Transformation{
+	testLine(0, 2, -0.3039722258743317, 2.952680894054774),
+	testLine(4, 0, 3.6960286315385957, 0.9526811676293918),
+	testLine(2, 0, 1.696028726616178, 0.9526811979657297),
+}
+
+// TestE checks the E-functions return InstabilityErrors for a Transformation
+// that can't be simplified and that String still describes it.
+func TestE(t *testing.T) {
+	u := unstableTransformation
+	_, err := SimplifyE(u)
+	checkInstability(t, err, errNotSimplified, 3)
+	_, err = TypeOfE(u)
+	checkInstability(t, err, errNotSimplified, 3)
+	_, err = DescribeE(u)
+	checkInstability(t, err, errNotSimplified, 3)
+	_, err = ApplyE(u, geometry.Point{X: 1, Y: 1})
+	checkInstability(t, err, errNotSimplified, 3)
+	_, err = PowerE(u, 2)
+	checkInstability(t, err, errNotSimplified, 3)
+	_, err = InverseE(Compose(u[2:], u[1:2], u[:1]))
+	checkInstability(t, err, errNotSimplified, 3)
+	_, err = ConjugateE(NoTransformation(), Compose(u[2:], u[1:2], u[:1]))
+	checkInstability(t, err, errNotSimplified, 3)
+	if s := u.String(); !strings.HasPrefix(s, "Compose(LineReflection(") {
+		t.Errorf("String is %s but should compose the line-reflections", s)
+	}
+}
+
+// TestApplyE checks ApplyE moves geometry.Points like Apply and returns an
+// InstabilityError when the geometry.Point isn't finite.
+func TestApplyE(t *testing.T) {
+	r := Rotation(geometry.Point{X: 1, Y: 0}, 1)
+	p := geometry.Point{X: 3, Y: -2}
+	got, err := ApplyE(r, p)
+	if want := Apply(r, p); err != nil || !geometry.AreEqual(got.X, want.X) ||
+		!geometry.AreEqual(got.Y, want.Y) {
+		t.Errorf("ApplyE(%v, %v) gives %v and %v but should give %v",
+			r, p, got, err, want)
+	}
+	huge := geometry.Point{X: 1e308, Y: 0}
+	_, err = ApplyE(Translation(geometry.Vector{I: 1e308, J: 0}), huge)
+	checkInstability(t, err, errNotFinite, 2)
+}
+
+// checkInstability fails the test if err isn't an InstabilityError caused by
+// cause with n geometry.Lines.
+func checkInstability(t *testing.T, err, cause error, n int) {
+	t.Helper()
+	var ie *InstabilityError
+	if !errors.As(err, &ie) {
+		t.Fatalf("got %v but should get an *InstabilityError", err)
+	}
+	if !errors.Is(err, ErrNumericalInstability) || !errors.Is(err, cause) {
+		t.Errorf("%v isn't %v and %v", err, ErrNumericalInstability, cause)
+	}
+	if len(ie.Lines) != n {
+		t.Errorf("%v carries %d geometry.Lines but should carry %d",
+			err, len(ie.Lines), n)
+	}
+}
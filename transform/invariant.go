@@ -0,0 +1,141 @@
+package transform
+
+import (
+	"math"
+
+	"github.com/jwowillo/viztransform/geometry"
+)
+
+// Kinds of Fixed geometry.Points.
+const (
+	// FixedNothing belongs to Fixed for Transformations that move every
+	// geometry.Point.
+	FixedNothing FixedKind = iota
+	// FixedPoint belongs to Fixed for Transformations that only keep a
+	// single geometry.Point in place.
+	//
+	// Fixed's Point is the geometry.Point.
+	FixedPoint
+	// FixedLine belongs to Fixed for Transformations that keep every
+	// geometry.Point on a geometry.Line in place.
+	//
+	// Fixed's Line is the geometry.Line.
+	FixedLine
+	// FixedPlane belongs to Fixed for Transformations that keep every
+	// geometry.Point in place.
+	FixedPlane
+)
+
+// FixedKind is the kind of set of geometry.Points kept in place by a
+// Transformation.
+type FixedKind int
+
+// Fixed is the set of geometry.Points a Transformation keeps in place.
+//
+// Only the fields described by the Kind are set.
+type Fixed struct {
+	Kind  FixedKind
+	Point geometry.Point
+	Line  geometry.Line
+}
+
+// Kinds of Invariant geometry.Lines.
+const (
+	// InvariantNothing belongs to Invariant for Transformations that don't
+	// map any geometry.Line onto itself without also keeping each of its
+	// geometry.Points in place.
+	InvariantNothing InvariantKind = iota
+	// InvariantLine belongs to Invariant for Transformations that map a
+	// single geometry.Line onto itself.
+	//
+	// Invariant's Line is the geometry.Line.
+	InvariantLine
+	// InvariantParallelLines belongs to Invariant for Transformations that
+	// map every geometry.Line parallel to a geometry.Line onto itself.
+	//
+	// Invariant's Line is one of the geometry.Lines.
+	InvariantParallelLines
+	// InvariantLinesThroughPoint belongs to Invariant for Transformations
+	// that map every geometry.Line through a geometry.Point onto itself.
+	//
+	// Invariant's Point is the geometry.Point.
+	InvariantLinesThroughPoint
+)
+
+// InvariantKind is the kind of set of geometry.Lines mapped onto themselves by
+// a Transformation.
+type InvariantKind int
+
+// Invariant is the set of geometry.Lines a Transformation maps onto themselves.
+//
+// Only the fields described by the Kind are set.
+type Invariant struct {
+	Kind  InvariantKind
+	Point geometry.Point
+	Line  geometry.Line
+}
+
+// FixedPoints returns the geometry.Points Transformation t keeps in place.
+//
+// They are the whole plane for a Transformation with TypeNoTransformation, the
+// geometry.Line reflected across for TypeLineReflection, the center for
+// TypeRotation, and nothing for TypeTranslation and TypeGlideReflection.
+func FixedPoints(t Transformation) Fixed {
+	return FixedPointsWithin(t, geometry.DefaultTolerance())
+}
+
+// FixedPointsWithin is FixedPoints where geometry.Lines are compared within
+// geometry.Tolerance tol.
+func FixedPointsWithin(t Transformation, tol geometry.Tolerance) Fixed {
+	switch p := DescribeWithin(t, tol).(type) {
+	case NoTransformationParams:
+		return Fixed{Kind: FixedPlane}
+	case LineReflectionParams:
+		return Fixed{Kind: FixedLine, Line: p.Line}
+	case RotationParams:
+		return Fixed{Kind: FixedPoint, Point: p.Center}
+	}
+	return Fixed{Kind: FixedNothing}
+}
+
+// InvariantLines returns the geometry.Lines Transformation t maps onto
+// themselves that aren't already kept in place by FixedPoints.
+//
+// They are nothing for a Transformation with TypeNoTransformation, every
+// geometry.Line perpendicular to the geometry.Line reflected across for
+// TypeLineReflection, every geometry.Line parallel to the geometry.Vector
+// translated by for TypeTranslation, and the geometry.Line reflected across
+// for TypeGlideReflection. They are every geometry.Line through the center for
+// TypeRotation by half a turn and nothing for other rotations.
+func InvariantLines(t Transformation) Invariant {
+	return InvariantLinesWithin(t, geometry.DefaultTolerance())
+}
+
+// InvariantLinesWithin is InvariantLines where geometry.Lines and angles are
+// compared within geometry.Tolerance tol.
+func InvariantLinesWithin(t Transformation, tol geometry.Tolerance) Invariant {
+	switch p := DescribeWithin(t, tol).(type) {
+	case LineReflectionParams:
+		return Invariant{
+			Kind: InvariantParallelLines,
+			Line: geometry.Perpendicular(p.Line),
+		}
+	case TranslationParams:
+		return Invariant{
+			Kind: InvariantParallelLines,
+			Line: geometry.MustLine(tol.NewLineFromPoints(
+				geometry.Point{X: 0, Y: 0},
+				geometry.Point{X: p.Vector.I, Y: p.Vector.J},
+			)),
+		}
+	case RotationParams:
+		half := math.Abs(math.Remainder(float64(p.Angle), 2*math.Pi))
+		if !tol.AreEqual(geometry.Number(half), math.Pi) {
+			break
+		}
+		return Invariant{Kind: InvariantLinesThroughPoint, Point: p.Center}
+	case GlideParams:
+		return Invariant{Kind: InvariantLine, Line: p.Axis}
+	}
+	return Invariant{Kind: InvariantNothing}
+}
@@ -0,0 +1,162 @@
+package transform
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jwowillo/viztransform/geometry"
+)
+
+// TestFixedPoints checks FixedPoints gives the geometry.Points each type of
+// Transformation keeps in place and that they are kept in place.
+func TestFixedPoints(t *testing.T) {
+	center := geometry.Point{X: 1, Y: 2}
+	cases := []struct {
+		t    Transformation
+		want Fixed
+	}{
+		{NoTransformation(), Fixed{Kind: FixedPlane}},
+		{
+			LineReflection(testLine(0, 1, 1, 2)),
+			Fixed{Kind: FixedLine, Line: testLine(0, 1, 1, 2)},
+		},
+		{
+			Compose(
+				LineReflection(testLine(0, 0, 1, 0)),
+				LineReflection(testLine(0, 3, 1, 3)),
+				LineReflection(testLine(5, 0, 5, 1)),
+			),
+			Fixed{Kind: FixedNothing},
+		},
+		{
+			Rotation(center, math.Pi/3),
+			Fixed{Kind: FixedPoint, Point: center},
+		},
+		{Translation(geometry.Vector{I: 1, J: -1}), Fixed{Kind: FixedNothing}},
+		{
+			GlideReflection(testLine(0, 0, 1, 1), geometry.Vector{I: 1, J: 1}),
+			Fixed{Kind: FixedNothing},
+		},
+	}
+	for _, c := range cases {
+		got := FixedPoints(c.t)
+		if got.Kind != c.want.Kind {
+			t.Errorf("%v keeps %v in place but should keep %v",
+				c.t, got, c.want)
+			continue
+		}
+		switch got.Kind {
+		case FixedPoint:
+			if !geometry.AreSamePoint(got.Point, c.want.Point) {
+				t.Errorf("%v keeps %v in place but should keep %v",
+					c.t, got.Point, c.want.Point)
+			}
+			checkKept(t, c.t, got.Point)
+		case FixedLine:
+			if !geometry.AreSameLine(got.Line, c.want.Line) {
+				t.Errorf("%v keeps %v in place but should keep %v",
+					c.t, got.Line, c.want.Line)
+			}
+			a, b := got.Line.Points()
+			checkKept(t, c.t, a)
+			checkKept(t, c.t, b)
+		}
+	}
+}
+
+// TestInvariantLines checks InvariantLines gives the geometry.Lines each type
+// of Transformation maps onto themselves and that they are.
+func TestInvariantLines(t *testing.T) {
+	center := geometry.Point{X: -1, Y: 3}
+	axis := testLine(0, 1, 2, 2)
+	cases := []struct {
+		t    Transformation
+		want Invariant
+	}{
+		{NoTransformation(), Invariant{Kind: InvariantNothing}},
+		{
+			LineReflection(axis),
+			Invariant{
+				Kind: InvariantParallelLines,
+				Line: geometry.Perpendicular(axis),
+			},
+		},
+		{
+			Translation(geometry.Vector{I: 3, J: 4}),
+			Invariant{
+				Kind: InvariantParallelLines,
+				Line: testLine(0, 0, 3, 4),
+			},
+		},
+		{
+			Rotation(center, math.Pi),
+			Invariant{Kind: InvariantLinesThroughPoint, Point: center},
+		},
+		{
+			Rotation(center, -math.Pi),
+			Invariant{Kind: InvariantLinesThroughPoint, Point: center},
+		},
+		{Rotation(center, math.Pi/2), Invariant{Kind: InvariantNothing}},
+		{
+			GlideReflection(axis, geometry.Vector{I: 2, J: 1}),
+			Invariant{Kind: InvariantLine, Line: axis},
+		},
+	}
+	for _, c := range cases {
+		got := InvariantLines(c.t)
+		if got.Kind != c.want.Kind {
+			t.Errorf("%v maps %v onto themselves but should map %v",
+				c.t, got, c.want)
+			continue
+		}
+		switch got.Kind {
+		case InvariantLine:
+			if !geometry.AreSameLine(got.Line, c.want.Line) {
+				t.Errorf("%v maps %v onto itself but should map %v",
+					c.t, got.Line, c.want.Line)
+			}
+			checkOnto(t, c.t, got.Line)
+		case InvariantParallelLines:
+			if !geometry.AreParallel(got.Line, c.want.Line) {
+				t.Errorf("%v maps lines parallel to %v onto themselves but "+
+					"should map ones parallel to %v", c.t, got.Line,
+					c.want.Line)
+			}
+			checkOnto(t, c.t, got.Line)
+			checkOnto(t, c.t, geometry.Shift(got.Line, geometry.Vector{
+				I: 5,
+				J: -2,
+			}))
+		case InvariantLinesThroughPoint:
+			if !geometry.AreSamePoint(got.Point, c.want.Point) {
+				t.Errorf("%v maps lines through %v onto themselves but "+
+					"should map ones through %v", c.t, got.Point,
+					c.want.Point)
+			}
+			checkKept(t, c.t, got.Point)
+		}
+	}
+}
+
+// checkKept fails the test if Transformation tr doesn't keep geometry.Point p
+// in place.
+func checkKept(t *testing.T, tr Transformation, p geometry.Point) {
+	t.Helper()
+	if q := Apply(tr, p); !geometry.AreSamePoint(p, q) {
+		t.Errorf("%v moves %v to %v", tr, p, q)
+	}
+}
+
+// checkOnto fails the test if Transformation tr doesn't map geometry.Line l
+// onto itself.
+func checkOnto(t *testing.T, tr Transformation, l geometry.Line) {
+	t.Helper()
+	a, b := l.Points()
+	got := geometry.MustLine(geometry.NewLineFromPoints(
+		Apply(tr, a),
+		Apply(tr, b),
+	))
+	if !geometry.AreSameLine(got, l) {
+		t.Errorf("%v maps %v to %v", tr, l, got)
+	}
+}
@@ -0,0 +1,93 @@
+package transform
+
+import (
+	"encoding/json"
+
+	"github.com/jwowillo/viztransform/geometry"
+)
+
+// MarshalJSON encodes the Transformation as the JSON-array of its
+// geometry.Lines.
+//
+// The Transformation isn't simplified and the geometry.Lines aren't rounded so
+// the same Transformation is decoded. The Params from Describe encode the Type
+// and values of the simplified form instead.
+func (t Transformation) MarshalJSON() ([]byte, error) {
+	if t == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]geometry.Line(t))
+}
+
+// UnmarshalJSON decodes the Transformation from the JSON-array of its
+// geometry.Lines.
+//
+// Returns the errors geometry.Line's UnmarshalJSON does for each geometry.Line.
+// A null JSON leaves the Transformation as it is.
+func (t *Transformation) UnmarshalJSON(bs []byte) error {
+	var ls []geometry.Line
+	if err := json.Unmarshal(bs, &ls); err != nil {
+		return err
+	}
+	if ls != nil {
+		*t = Transformation(ls)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the Params as a JSON-object like
+// '{"type": "NoTransformation"}'.
+func (NoTransformationParams) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type string `json:"type"`
+	}{"NoTransformation"})
+}
+
+// MarshalJSON encodes the Params as a JSON-object like
+// '{"type": "LineReflection", "line": geometry.Line}'.
+func (p LineReflectionParams) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type string        `json:"type"`
+		Line geometry.Line `json:"line"`
+	}{"LineReflection", p.Line})
+}
+
+// MarshalJSON encodes the Params as a JSON-object like
+// '{"type": "Translation", "vector": geometry.Vector}'.
+func (p TranslationParams) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type   string          `json:"type"`
+		Vector geometry.Vector `json:"vector"`
+	}{"Translation", p.Vector})
+}
+
+// MarshalJSON encodes the Params as a JSON-object like
+// '{"type": "Rotation", "center": geometry.Point, "angle": geometry.Angle}'
+// where the geometry.Angle is in radians.
+func (p RotationParams) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type   string         `json:"type"`
+		Center geometry.Point `json:"center"`
+		Angle  float64        `json:"angle"`
+	}{"Rotation", p.Center, jsonAngle(p.Angle)})
+}
+
+// jsonAngle returns geometry.Angle a in radians with -0 as 0 so it isn't
+// encoded as '-0'.
+func jsonAngle(a geometry.Angle) float64 {
+	if a == 0 {
+		return 0
+	}
+	return float64(a)
+}
+
+// MarshalJSON encodes the Params as a JSON-object like
+// '{"type": "GlideReflection", "axis": geometry.Line, "vector":
+// geometry.Vector}'.
+func (p GlideParams) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type   string          `json:"type"`
+		Axis   geometry.Line   `json:"axis"`
+		Vector geometry.Vector `json:"vector"`
+	}{"GlideReflection", p.Axis, p.Vector})
+}
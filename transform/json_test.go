@@ -0,0 +1,82 @@
+package transform
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/jwowillo/viztransform/geometry"
+)
+
+// TestTransformationJSON checks a Transformation decodes from its JSON to the
+// same geometry.Lines even if their geometry.Points are closer than the
+// geometry.DefaultTolerance.
+func TestTransformationJSON(t *testing.T) {
+	near := geometry.MustLine(geometry.Tolerance{}.NewLineFromPoints(
+		geometry.Point{},
+		geometry.Point{X: 1e-9},
+	))
+	cases := []Transformation{
+		LineReflection(near),
+		NoTransformation(),
+		LineReflection(testLine(0, 0, 1, 0)),
+		Rotation(geometry.Point{X: 0.1, Y: 1.0 / 3}, math.Pi/7),
+		GlideReflection(testLine(1, 2, 3, 5), geometry.Vector{I: 2, J: 3}),
+	}
+	for _, want := range cases {
+		bs, err := json.Marshal(want)
+		if err != nil {
+			t.Fatalf("%v gives %v", want, err)
+		}
+		var got Transformation
+		if err := json.Unmarshal(bs, &got); err != nil {
+			t.Fatalf("%s gives %v", bs, err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("%s decodes to %v but should decode to %v", bs, got, want)
+		}
+		for i := range want {
+			wa, wb := want[i].Points()
+			ga, gb := got[i].Points()
+			if wa != ga || wb != gb {
+				t.Errorf("%s decodes to %v but should decode to %v",
+					bs, got, want)
+			}
+		}
+	}
+}
+
+// TestTransformationJSONBad checks a Transformation isn't decoded from JSON
+// that isn't a JSON-array of geometry.Lines.
+func TestTransformationJSONBad(t *testing.T) {
+	for _, x := range []string{
+		`{}`,
+		`[{"a": {"x": 0, "y": 0}}]`,
+		`[{"a": {"x": 0, "y": 0}, "b": {"x": 0, "y": 0}}]`,
+	} {
+		var got Transformation
+		if err := json.Unmarshal([]byte(x), &got); err == nil {
+			t.Errorf("%s decodes to %v but should give an error", x, got)
+		}
+	}
+}
+
+// TestParamsJSONNegativeZero checks -0 is encoded as 0.
+func TestParamsJSONNegativeZero(t *testing.T) {
+	zero := geometry.Number(math.Copysign(0, -1))
+	cases := []Params{
+		TranslationParams{Vector: geometry.Vector{I: zero, J: 1}},
+		RotationParams{Center: geometry.Point{X: zero, Y: zero}, Angle: 1},
+		RotationParams{Angle: geometry.Angle(zero)},
+	}
+	for _, p := range cases {
+		bs, err := json.Marshal(p)
+		if err != nil {
+			t.Fatalf("%v gives %v", p, err)
+		}
+		if strings.Contains(string(bs), "-0") {
+			t.Errorf("%v encodes to %s which has -0", p, bs)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package transform
+
+import (
+	"errors"
+	"math"
+
+	"github.com/jwowillo/viztransform/geometry"
+)
+
+// ErrNotIsometry is returned when a Matrix given to something expecting a
+// Matrix that preserves distances doesn't.
+var ErrNotIsometry = errors.New("Matrix isn't an isometry")
+
+// Matrix is a 3x3 matrix in homogeneous coordinates representing an affine
+// map of the plane.
+//
+// Matrices are indexed by row and then column. geometry.Points are treated as
+// the column (X, Y, 1) and are mapped by multiplying the Matrix on the left of
+// the column.
+type Matrix [3][3]geometry.Number
+
+// Identity returns the Matrix that leaves every geometry.Point in place.
+func Identity() Matrix {
+	return Matrix{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+}
+
+// Multiply Matrices a and b into the Matrix that maps geometry.Points by b and
+// then by a.
+func Multiply(a, b Matrix) Matrix {
+	var m Matrix
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				m[i][j] += a[i][k] * b[k][j]
+			}
+		}
+	}
+	return m
+}
+
+// ApplyMatrix maps geometry.Point p by Matrix m.
+//
+// Doesn't do any intersections so is much faster than Apply when the same
+// Transformation is applied to many geometry.Points.
+func ApplyMatrix(m Matrix, p geometry.Point) geometry.Point {
+	return geometry.Point{
+		X: m[0][0]*p.X + m[0][1]*p.Y + m[0][2],
+		Y: m[1][0]*p.X + m[1][1]*p.Y + m[1][2],
+	}
+}
+
+// ToMatrix returns the Matrix that maps geometry.Points the same way
+// Transformation t does.
+func ToMatrix(t Transformation) Matrix {
+	m := Identity()
+	for _, l := range t {
+		m = Multiply(reflectionMatrix(l), m)
+	}
+	return m
+}
+
+// FromMatrix returns the simplified Transformation that maps geometry.Points
+// the same way Matrix m does.
+//
+// Returns ErrNotIsometry if m doesn't preserve distances, which is when the
+// bottom row isn't (0 0 1) or the top-left 2x2 part isn't orthonormal.
+func FromMatrix(m Matrix) (Transformation, error) {
+	return FromMatrixWithin(m, geometry.DefaultTolerance())
+}
+
+// FromMatrixWithin is FromMatrix where geometry.Numbers are compared within
+// geometry.Tolerance tol.
+//
+// Useful for Matrices written with few digits like ones in SVG files.
+func FromMatrixWithin(
+	m Matrix,
+	tol geometry.Tolerance,
+) (Transformation, error) {
+	if !IsIsometryWithin(m, tol) {
+		return nil, ErrNotIsometry
+	}
+	rads := math.Atan2(float64(m[1][0]), float64(m[0][0]))
+	v := geometry.Vector{I: m[0][2], J: m[1][2]}
+	if det(m) > 0 {
+		return fromProperMatrix(rads, v, tol), nil
+	}
+	return fromImproperMatrix(rads, v, tol), nil
+}
+
+// IsIsometry returns true if Matrix m preserves distances between
+// geometry.Points.
+func IsIsometry(m Matrix) bool {
+	return IsIsometryWithin(m, geometry.DefaultTolerance())
+}
+
+// IsIsometryWithin is IsIsometry where geometry.Numbers are compared within
+// geometry.Tolerance tol.
+func IsIsometryWithin(m Matrix, tol geometry.Tolerance) bool {
+	a, b, c, d := m[0][0], m[0][1], m[1][0], m[1][1]
+	return tol.IsZero(m[2][0]) && tol.IsZero(m[2][1]) &&
+		tol.AreEqual(m[2][2], 1) &&
+		tol.AreEqual(a*a+c*c, 1) && tol.AreEqual(b*b+d*d, 1) &&
+		tol.IsZero(a*b+c*d)
+}
+
+// fromProperMatrix returns the simplified Transformation for an isometry that
+// rotates counter-clockwise by rads around the origin and then translates by
+// geometry.Vector v where geometry.Numbers are compared within
+// geometry.Tolerance tol.
+//
+// The result has TypeNoTransformation, TypeTranslation, or TypeRotation.
+func fromProperMatrix(
+	rads float64,
+	v geometry.Vector,
+	tol geometry.Tolerance,
+) Transformation {
+	if tol.IsZero(geometry.Number(rads)) {
+		return TranslationWithin(v, tol)
+	}
+	cos, sin := geometry.Number(math.Cos(rads)), geometry.Number(math.Sin(rads))
+	d := 2 - 2*cos
+	c := geometry.Point{
+		X: ((1-cos)*v.I - sin*v.J) / d,
+		Y: (sin*v.I + (1-cos)*v.J) / d,
+	}
+	return RotationWithin(c, geometry.Angle(rads), tol)
+}
+
+// fromImproperMatrix returns the simplified Transformation for an isometry that
+// reflects across the geometry.Line through the origin at angle rads/2 and
+// then translates by geometry.Vector v where geometry.Numbers are compared
+// within geometry.Tolerance tol.
+//
+// The result has TypeLineReflection or TypeGlideReflection.
+func fromImproperMatrix(
+	rads float64,
+	v geometry.Vector,
+	tol geometry.Tolerance,
+) Transformation {
+	u := geometry.Vector{
+		I: geometry.Number(math.Cos(rads / 2)),
+		J: geometry.Number(math.Sin(rads / 2)),
+	}
+	along := u.I*v.I + u.J*v.J
+	parallel := geometry.Vector{I: along * u.I, J: along * u.J}
+	p := geometry.Point{X: (v.I - parallel.I) / 2, Y: (v.J - parallel.J) / 2}
+	axis := geometry.MustLine(tol.NewLineFromPoints(
+		p,
+		geometry.Point{X: p.X + u.I, Y: p.Y + u.J},
+	))
+	return GlideReflectionWithin(axis, parallel, tol)
+}
+
+// reflectionMatrix returns the Matrix that reflects geometry.Points across
+// geometry.Line l.
+func reflectionMatrix(l geometry.Line) Matrix {
+	a, b, c := geometry.StandardCoefficients(l)
+	n := a*a + b*b
+	return Matrix{
+		{1 - 2*a*a/n, -2 * a * b / n, 2 * a * c / n},
+		{-2 * a * b / n, 1 - 2*b*b/n, 2 * b * c / n},
+		{0, 0, 1},
+	}
+}
+
+// det is the determinant of the top-left 2x2 part of Matrix m.
+func det(m Matrix) geometry.Number {
+	return m[0][0]*m[1][1] - m[0][1]*m[1][0]
+}
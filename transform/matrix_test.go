@@ -0,0 +1,40 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/jwowillo/viztransform/geometry"
+)
+
+// TestFromMatrixWithin checks the geometry.Tolerance passed to
+// FromMatrixWithin decides what's small enough to drop everywhere.
+func TestFromMatrixWithin(t *testing.T) {
+	tight := geometry.Tolerance{Absolute: 1e-12}
+	loose := geometry.Tolerance{Absolute: 1e-3}
+	reflection := LineReflection(testLine(0, 1, 1, 2))
+	glide := GlideReflection(testLine(0, 1, 1, 2), geometry.Vector{I: 1e-4})
+	cases := []struct {
+		want Transformation
+		tol  geometry.Tolerance
+		n    int
+	}{
+		{RotationWithin(geometry.Point{X: 1000}, 1e-8, tight), tight, 2},
+		{TranslationWithin(geometry.Vector{I: 1e-9}, tight), tight, 2},
+		{glide, loose, 1},
+		{reflection, tight, 1},
+		{glide, tight, 3},
+	}
+	for _, c := range cases {
+		got, err := FromMatrixWithin(ToMatrix(c.want), c.tol)
+		if err != nil {
+			t.Fatalf("%v gives %v", c.want, err)
+		}
+		if len(got) != c.n {
+			t.Errorf("%v has %d line-reflections but should have %d",
+				got, len(got), c.n)
+		}
+		if c.tol == tight {
+			checkSame(t, c.want, got)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package transform
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/jwowillo/viztransform/geometry"
+)
+
+// parallelLines is the fewest geometry.Lines SimplifyParallel gives a worker.
+//
+// Simplifying this many geometry.Lines takes milliseconds so a worker costs
+// little compared to the work it does.
+const parallelLines = 1 << 12
+
+// chunkSize is the number of geometry.Points each worker in ApplyAll maps at a
+// time.
+//
+// Chunks are big enough that handing them out costs little compared to mapping
+// them.
+const chunkSize = 1 << 12
+
+// ApplyAll applies Transformation t to every geometry.Point in ps and returns
+// the moved geometry.Points in the same order.
+//
+// t is turned into a Matrix once so each geometry.Point only costs a few
+// multiplications. The geometry.Points are split into chunks that are mapped
+// concurrently by as many workers as runtime.GOMAXPROCS allows. ps isn't
+// changed.
+func ApplyAll(t Transformation, ps []geometry.Point) []geometry.Point {
+	m := ToMatrix(t)
+	out := make([]geometry.Point, len(ps))
+	workers := runtime.GOMAXPROCS(0)
+	if chunks := (len(ps) + chunkSize - 1) / chunkSize; chunks < workers {
+		workers = chunks
+	}
+	if workers <= 1 {
+		applyChunk(m, ps, out)
+		return out
+	}
+	starts := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for start := range starts {
+				end := start + chunkSize
+				if end > len(ps) {
+					end = len(ps)
+				}
+				applyChunk(m, ps[start:end], out[start:end])
+			}
+		}()
+	}
+	for start := 0; start < len(ps); start += chunkSize {
+		starts <- start
+	}
+	close(starts)
+	wg.Wait()
+	return out
+}
+
+// applyChunk maps every geometry.Point in ps by Matrix m into the same index
+// of out.
+func applyChunk(m Matrix, ps, out []geometry.Point) {
+	for i, p := range ps {
+		out[i] = ApplyMatrix(m, p)
+	}
+}
+
+// SimplifyParallel is Simplify for long Transformations that splits t into a
+// chunk for each worker, simplifies the chunks concurrently, and then
+// simplifies the composition of the simplified chunks in order.
+//
+// This works since composing line-reflections is associative so any parts can
+// be simplified first. There are as many workers as runtime.GOMAXPROCS allows
+// but each gets at least parallelLines geometry.Lines so starting it costs
+// little compared to simplifying its chunk. t is simplified like Simplify does
+// if there would only be a single worker. The result expresses the same
+// Transformation as Simplify but the geometry.Lines can be different.
+func SimplifyParallel(t Transformation) Transformation {
+	return SimplifyParallelWithin(t, geometry.DefaultTolerance())
+}
+
+// SimplifyParallelWithin is SimplifyParallel where geometry.Lines are compared
+// within geometry.Tolerance tol.
+//
+// Panics like SimplifyWithin.
+func SimplifyParallelWithin(
+	t Transformation,
+	tol geometry.Tolerance,
+) Transformation {
+	workers := runtime.GOMAXPROCS(0)
+	if most := len(t) / parallelLines; most < workers {
+		workers = most
+	}
+	if workers <= 1 || IsSimplifiedWithin(t, tol) {
+		return SimplifyWithin(t, tol)
+	}
+	s, err := simplifier{tol: tol}.simplifyChunks(t, workers)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// simplifyChunks simplifies Transformation t by splitting it into n chunks of
+// about the same length, simplifying each concurrently, and then simplifying
+// their composition one chunk at a time.
+//
+// The error from simplifying the first chunk that fails is returned.
+func (s simplifier) simplifyChunks(
+	t Transformation,
+	n int,
+) (Transformation, error) {
+	chunks := make([]Transformation, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := range chunks {
+		go func(i int) {
+			defer wg.Done()
+			chunks[i], errs[i] = s.simplify(t[i*len(t)/n : (i+1)*len(t)/n])
+		}(i)
+	}
+	wg.Wait()
+	simplified := NoTransformation()
+	for i, chunk := range chunks {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		var err error
+		simplified, err = s.simplify(Compose(simplified, chunk))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return simplified, nil
+}
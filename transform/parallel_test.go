@@ -0,0 +1,194 @@
+package transform
+
+import (
+	"math/rand"
+	"runtime"
+	"testing"
+
+	"github.com/jwowillo/viztransform/geometry"
+)
+
+// benchmarkPoints is the number of geometry.Points mapped by the
+// Apply-benchmarks.
+const benchmarkPoints = 1 << 20
+
+// spreadPoints returns n geometry.Points spread over a grid around the origin.
+func spreadPoints(n int) []geometry.Point {
+	ps := make([]geometry.Point, n)
+	for i := range ps {
+		ps[i] = geometry.Point{
+			X: geometry.Number(i%1000) - 500,
+			Y: geometry.Number(i/1000%1000)/4 - 125,
+		}
+	}
+	return ps
+}
+
+// applyTransformation is a Transformation that isn't simplified mapped by the
+// Apply-benchmarks.
+var applyTransformation = Transformation{
+	testLine(0, 0, 1, 2),
+	testLine(1, 0, 1, 1),
+	testLine(0, 4, 3, 5),
+	testLine(-2, 0, 0, 3),
+}
+
+// TestApplyAll checks ApplyAll maps every geometry.Point to the same
+// geometry.Point as Apply including across chunks.
+func TestApplyAll(t *testing.T) {
+	for _, n := range []int{0, 1, chunkSize, 3*chunkSize + 17} {
+		ps := spreadPoints(n)
+		got := ApplyAll(applyTransformation, ps)
+		if len(got) != len(ps) {
+			t.Fatalf("%d geometry.Points map to %d", len(ps), len(got))
+		}
+		for i, p := range ps {
+			want := Apply(applyTransformation, p)
+			if !geometry.AreSamePoint(got[i], want) {
+				t.Fatalf("%v maps to %v but should map to %v",
+					p, got[i], want)
+			}
+		}
+	}
+}
+
+// BenchmarkApply maps benchmarkPoints geometry.Points one at a time.
+func BenchmarkApply(b *testing.B) {
+	ps := spreadPoints(benchmarkPoints)
+	out := make([]geometry.Point, len(ps))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j, p := range ps {
+			out[j] = Apply(applyTransformation, p)
+		}
+	}
+}
+
+// BenchmarkApplyAll maps the same geometry.Points as BenchmarkApply with
+// ApplyAll.
+func BenchmarkApplyAll(b *testing.B) {
+	ps := spreadPoints(benchmarkPoints)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ApplyAll(applyTransformation, ps)
+	}
+}
+
+// manyLines returns a Transformation of n geometry.Lines through points near
+// the origin that's the same every time.
+func manyLines(n int) Transformation {
+	return randomLines(rand.New(rand.NewSource(1)), n)
+}
+
+// randomLines returns a Transformation of n geometry.Lines from r through
+// points near the origin.
+//
+// The geometry.Lines are never close to vertical so they're well-conditioned.
+func randomLines(r *rand.Rand, n int) Transformation {
+	t := make(Transformation, n)
+	for i := range t {
+		ax, ay := r.Float64()*10-5, r.Float64()*10-5
+		bx, by := ax+r.Float64()+0.5, ay+r.Float64()*2-1
+		t[i] = testLine(
+			geometry.Number(ax), geometry.Number(ay),
+			geometry.Number(bx), geometry.Number(by),
+		)
+	}
+	return t
+}
+
+// regrouped is the geometry.Tolerance Transformations simplified in different
+// groups are compared within since rounding builds up differently in each.
+var regrouped = geometry.Tolerance{Absolute: 1e-3}
+
+// TestSimplifyParallel checks SimplifyParallel expresses the same
+// Transformation as Simplify when t is long enough to be split between several
+// workers.
+//
+// t repeats a random block of geometry.Lines so it stays well-conditioned no
+// matter how long it is.
+func TestSimplifyParallel(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	r := rand.New(rand.NewSource(2))
+	for _, n := range []int{
+		parallelLines - 1,
+		2 * parallelLines,
+		3*parallelLines + 17,
+		5 * parallelLines,
+	} {
+		for i := 0; i < 3; i++ {
+			block := randomLines(r, 4)
+			tr := make(Transformation, n)
+			for j := range tr {
+				tr[j] = block[j%len(block)]
+			}
+			want, got := Simplify(tr), SimplifyParallel(tr)
+			if !IsSimplified(got) {
+				t.Errorf("%v isn't simplified", got)
+			}
+			if !EqualWithin(got, want, regrouped) {
+				t.Errorf("%d geometry.Lines simplify to %v but should "+
+					"simplify to %v", n, got, want)
+			}
+		}
+	}
+}
+
+// TestSimplifyChunks checks splitting random geometry.Lines between several
+// workers expresses the same Transformation as Simplify.
+func TestSimplifyChunks(t *testing.T) {
+	s := simplifier{tol: geometry.DefaultTolerance()}
+	r := rand.New(rand.NewSource(3))
+	for _, n := range []int{65, 130, 301} {
+		tr := randomLines(r, n)
+		want := Simplify(tr)
+		for workers := 2; workers <= 5; workers++ {
+			got, err := s.simplifyChunks(tr, workers)
+			if err != nil {
+				t.Errorf("%d geometry.Lines between %d workers give %v",
+					n, workers, err)
+				continue
+			}
+			if !EqualWithin(got, want, regrouped) {
+				t.Errorf("%d geometry.Lines between %d workers simplify to "+
+					"%v but should simplify to %v", n, workers, got, want)
+			}
+		}
+	}
+}
+
+// benchmarkSimplify simplifies a Transformation of n geometry.Lines with
+// simplify.
+func benchmarkSimplify(
+	b *testing.B,
+	n int,
+	simplify func(Transformation) Transformation,
+) {
+	t := manyLines(n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		simplify(t)
+	}
+}
+
+// BenchmarkSimplify10k simplifies 10 thousand geometry.Lines with Simplify.
+func BenchmarkSimplify10k(b *testing.B) {
+	benchmarkSimplify(b, 10000, Simplify)
+}
+
+// BenchmarkSimplifyParallel10k simplifies the same geometry.Lines as
+// BenchmarkSimplify10k with SimplifyParallel.
+func BenchmarkSimplifyParallel10k(b *testing.B) {
+	benchmarkSimplify(b, 10000, SimplifyParallel)
+}
+
+// BenchmarkSimplify1M simplifies a million geometry.Lines with Simplify.
+func BenchmarkSimplify1M(b *testing.B) {
+	benchmarkSimplify(b, 1000000, Simplify)
+}
+
+// BenchmarkSimplifyParallel1M simplifies the same geometry.Lines as
+// BenchmarkSimplify1M with SimplifyParallel.
+func BenchmarkSimplifyParallel1M(b *testing.B) {
+	benchmarkSimplify(b, 1000000, SimplifyParallel)
+}
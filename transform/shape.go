@@ -0,0 +1,46 @@
+package transform
+
+import (
+	"github.com/jwowillo/viztransform/geometry"
+)
+
+// ApplySegment applies the Transformation to both ends of the geometry.Segment.
+func ApplySegment(t Transformation, s geometry.Segment) geometry.Segment {
+	return geometry.Segment{A: Apply(t, s.A), B: Apply(t, s.B)}
+}
+
+// ApplyRay applies the Transformation to the geometry.Ray.
+//
+// The origin is moved by the Transformation and the direction is turned the
+// same way the Transformation turns everything.
+func ApplyRay(t Transformation, r geometry.Ray) geometry.Ray {
+	o := Apply(t, r.Origin)
+	p := Apply(t, geometry.Point{
+		X: r.Origin.X + r.Direction.I,
+		Y: r.Origin.Y + r.Direction.J,
+	})
+	return geometry.Ray{
+		Origin:    o,
+		Direction: geometry.Vector{I: p.X - o.X, J: p.Y - o.Y},
+	}
+}
+
+// ApplyPolygon applies the Transformation to every vertex of the
+// geometry.Polygon.
+//
+// The vertices keep their order so they go around the other way if t has an
+// odd number of line-reflections.
+func ApplyPolygon(t Transformation, p geometry.Polygon) geometry.Polygon {
+	out := make(geometry.Polygon, len(p))
+	for i, v := range p {
+		out[i] = Apply(t, v)
+	}
+	return out
+}
+
+// ApplyCircle applies the Transformation to the geometry.Circle.
+//
+// Only the center moves since Transformations don't change distances.
+func ApplyCircle(t Transformation, c geometry.Circle) geometry.Circle {
+	return geometry.Circle{Center: Apply(t, c.Center), Radius: c.Radius}
+}
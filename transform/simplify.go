@@ -1,6 +1,7 @@
 package transform
 
 import (
+	"errors"
 	"math"
 
 	"github.com/jwowillo/viztransform/geometry"
@@ -9,18 +10,24 @@ import (
 // IsSimplified returns true if the Transformation t uses the minimum number of
 // line-reflections to achieve the result of the Transformation.
 func IsSimplified(t Transformation) bool {
+	return IsSimplifiedWithin(t, geometry.DefaultTolerance())
+}
+
+// IsSimplifiedWithin is IsSimplified where geometry.Lines are compared within
+// geometry.Tolerance tol.
+func IsSimplifiedWithin(t Transformation, tol geometry.Tolerance) bool {
 	if len(t) < 2 {
 		return true
 	}
 	if len(t) == 2 {
-		return !geometry.AreSameLine(t[0], t[1])
+		return !tol.AreSameLine(t[0], t[1])
 	}
 	if len(t) == 3 {
 		a, b, c := t[0], t[1], t[2]
-		return geometry.ArePerpendicular(a, b) &&
-			geometry.AreParallel(b, c) ||
-			geometry.AreParallel(a, b) &&
-				geometry.ArePerpendicular(b, c)
+		return tol.ArePerpendicular(a, b) &&
+			tol.AreParallel(b, c) && !tol.AreSameLine(b, c) ||
+			tol.AreParallel(a, b) && !tol.AreSameLine(a, b) &&
+				tol.ArePerpendicular(b, c)
 	}
 	return false
 }
@@ -28,28 +35,99 @@ func IsSimplified(t Transformation) bool {
 // Simplify Transformation t into its simplest form that expresses the same
 // Transformation.
 //
+// t is returned as is if it's already simplified.
+//
 // The algorithm is documented at
 // https://github.com/jwowillo/viztransform/blob/master/doc/algorithm.pdf.
 func Simplify(t Transformation) Transformation {
-	if len(t) < 2 {
+	return SimplifyWithin(t, geometry.DefaultTolerance())
+}
+
+// SimplifyWithin is Simplify where geometry.Lines are compared within
+// geometry.Tolerance tol.
+//
+// Panics with the InstabilityError SimplifyWithinE would return if t's
+// geometry.Lines are so close to being degenerate that simplifying them fails.
+func SimplifyWithin(t Transformation, tol geometry.Tolerance) Transformation {
+	if IsSimplifiedWithin(t, tol) {
 		return t
 	}
+	s, err := simplifier{tol: tol}.simplify(t)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// SimplifyE is Simplify that returns an error instead of panicking.
+//
+// Returns an InstabilityError if t's geometry.Lines are so close to being
+// degenerate that Simplify fails or gives a Transformation that isn't
+// simplified.
+func SimplifyE(t Transformation) (Transformation, error) {
+	return SimplifyWithinE(t, geometry.DefaultTolerance())
+}
+
+// SimplifyWithinE is SimplifyE where geometry.Lines are compared within
+// geometry.Tolerance tol.
+func SimplifyWithinE(
+	t Transformation,
+	tol geometry.Tolerance,
+) (Transformation, error) {
+	if IsSimplifiedWithin(t, tol) {
+		return t, nil
+	}
+	s, err := simplifier{tol: tol}.simplify(t)
+	if err != nil {
+		return nil, err
+	}
+	if !IsSimplifiedWithin(s, tol) {
+		return nil, &InstabilityError{Lines: s, Err: errNotSimplified}
+	}
+	return s, nil
+}
+
+// errNotSimplified is the cause of an InstabilityError when simplifying gives a
+// Transformation that isn't simplified.
+var errNotSimplified = errors.New("simplified Transformation isn't simplified")
+
+// simplifier simplifies Transformations with geometry.Lines compared within
+// geometry.Tolerance tol.
+//
+// Returns an InstabilityError carrying the geometry.Lines being worked with if
+// geometry.Lines that must intersect or be different don't within tol.
+type simplifier struct {
+	tol geometry.Tolerance
+}
+
+// simplify Transformation t into its simplest form.
+func (s simplifier) simplify(t Transformation) (Transformation, error) {
+	if len(t) < 2 {
+		return t, nil
+	}
 	if len(t) == 2 {
-		return simplify2(t[0], t[1])
+		return s.simplify2(t[0], t[1]), nil
 	}
 	if len(t) == 3 {
-		return simplify3(t[0], t[1], t[2])
+		return s.simplify3(t[0], t[1], t[2])
 	}
-	return Simplify(Compose(
-		Simplify(t[:len(t)-4]),
-		simplify4(t[len(t)-4], t[len(t)-3], t[len(t)-2], t[len(t)-1]),
-	))
+	head, err := s.simplify(t[:len(t)-4])
+	if err != nil {
+		return nil, err
+	}
+	tail, err := s.simplify4(
+		t[len(t)-4], t[len(t)-3], t[len(t)-2], t[len(t)-1],
+	)
+	if err != nil {
+		return nil, err
+	}
+	return s.simplify(Compose(head, tail))
 }
 
 // simplify2 simplifies a Transformation represented by geometry.Lines a and b
 // into its simplest form.
-func simplify2(a, b geometry.Line) Transformation {
-	if geometry.AreSameLine(a, b) {
+func (s simplifier) simplify2(a, b geometry.Line) Transformation {
+	if s.tol.AreSameLine(a, b) {
 		return Transformation{}
 	}
 	return Transformation{a, b}
@@ -57,72 +135,110 @@ func simplify2(a, b geometry.Line) Transformation {
 
 // simplify3 simplifies a Transformation represented by geometry.Lines a, b, and
 // c into its simplest form.
-func simplify3(a, b, c geometry.Line) Transformation {
-	if len(simplify2(a, b)) == 0 {
-		return Transformation{c}
+func (s simplifier) simplify3(a, b, c geometry.Line) (Transformation, error) {
+	if len(s.simplify2(a, b)) == 0 {
+		return Transformation{c}, nil
 	}
-	if len(simplify2(b, c)) == 0 {
-		return Transformation{a}
+	if len(s.simplify2(b, c)) == 0 {
+		return Transformation{a}, nil
 	}
-	if geometry.AreParallel(a, b) && geometry.AreParallel(b, c) {
-		return Transformation{shiftBToC(a, b, c)}
+	if s.tol.AreParallel(a, b) && s.tol.AreParallel(b, c) {
+		return Transformation{s.shiftBToC(a, b, c)}, nil
 	}
-	a, b, c = rotateToParallelAndPerpendicular(a, b, c)
-	return Compose(simplify2(a, b), Transformation{c})
+	a, b, c, err := s.rotateToParallelAndPerpendicular(a, b, c)
+	if err != nil {
+		return nil, err
+	}
+	return Compose(s.simplify2(a, b), Transformation{c}), nil
 }
 
 // simplify4 simplifies a Transformation represented by geometry.Lines a, b, c,
 // and d into its simplest form.
-func simplify4(a, b, c, d geometry.Line) Transformation {
-	f3 := simplify3(a, b, c)
+func (s simplifier) simplify4(a, b, c, d geometry.Line) (Transformation, error) {
+	f3, err := s.simplify3(a, b, c)
+	if err != nil {
+		return nil, err
+	}
 	if len(f3) < 3 {
-		return Simplify(Compose(f3, Transformation{d}))
+		return s.simplify(Compose(f3, Transformation{d}))
+	}
+	l3, err := s.simplify3(b, c, d)
+	if err != nil {
+		return nil, err
 	}
-	l3 := simplify3(b, c, d)
 	if len(l3) < 3 {
-		return Simplify(Compose(Transformation{a}, l3))
+		return s.simplify(Compose(Transformation{a}, l3))
 	}
 	a, b, c = f3[0], f3[1], f3[2]
-	if geometry.AreParallel(b, d) {
-		return Transformation{shiftBToC(a, b, d), c}
+	if s.tol.AreParallel(b, d) {
+		return Transformation{s.shiftBToC(a, b, d), c}, nil
+	}
+	a, d, err = s.rotateBCToSame(a, c, b, d)
+	if err != nil {
+		return nil, err
 	}
-	a, d = rotateBCToSame(a, c, b, d)
-	return Transformation{a, d}
+	return Transformation{a, d}, nil
 }
 
 // rotateBCToSame takes geometry.Lines a, b, c, and d representing a rotation
 // with a and b and a rotation with c and d and simplifies them to a single
 // rotation by turning the rotations so b and c are the same and cancel.
-func rotateBCToSame(a, b, c, d geometry.Line) (geometry.Line, geometry.Line) {
-	ia := geometry.MustPoint(geometry.Intersection(a, b))
-	ib := geometry.MustPoint(geometry.Intersection(c, d))
-	l := geometry.MustLine(geometry.NewLineFromPoints(ia, ib))
-	radsa, radsb := geometry.AngleBetween(b, l), geometry.AngleBetween(c, l)
-	return geometry.Rotate(a, ia, radsa), geometry.Rotate(d, ib, radsb)
+func (s simplifier) rotateBCToSame(
+	a, b, c, d geometry.Line,
+) (geometry.Line, geometry.Line, error) {
+	ia, err := s.tol.Intersection(a, b)
+	if err != nil {
+		return geometry.Line{}, geometry.Line{}, unstable(err, a, b)
+	}
+	ib, err := s.tol.Intersection(c, d)
+	if err != nil {
+		return geometry.Line{}, geometry.Line{}, unstable(err, c, d)
+	}
+	l, err := s.tol.NewLineFromPoints(ia, ib)
+	if err != nil {
+		return geometry.Line{}, geometry.Line{}, unstable(err, a, b, c, d)
+	}
+	radsa, radsb := s.tol.AngleBetween(b, l), s.tol.AngleBetween(c, l)
+	return geometry.Rotate(a, ia, radsa), geometry.Rotate(d, ib, radsb), nil
 }
 
 // shiftBToC takes geometry.Lines a, b, and c representing line-reflections and
 // simplifies them to a single line-reflection by shifting a and b by the
 // geometry.Vector that makes b the same as c causing b and c to cancel.
-func shiftBToC(a, b, c geometry.Line) geometry.Line {
-	return geometry.Shift(a, geometry.ShortestVector(b, c))
+func (s simplifier) shiftBToC(a, b, c geometry.Line) geometry.Line {
+	return geometry.Shift(a, s.tol.ShortestVector(b, c))
 }
 
 // rotateToParallelAndPerpendicular takes geometry.Lines a, b, and c
 // representing line-reflections with at least one pair of geometry.Lines
 // intersecting and rotates them so that the first two returned geometry.Lines
 // are parallel and the second two are perpendicular.
-func rotateToParallelAndPerpendicular(
+//
+// If a and b are parallel, b and c are first rotated together so that a and b
+// intersect.
+func (s simplifier) rotateToParallelAndPerpendicular(
 	a, b, c geometry.Line,
-) (geometry.Line, geometry.Line, geometry.Line) {
-	if geometry.AreParallel(a, b) {
-		a, b, c = c, a, b
-	}
-	rads := geometry.AngleBetween(b, c)
-	i := geometry.MustPoint(geometry.Intersection(a, b))
-	a = geometry.Rotate(a, i, math.Pi/2-rads)
-	b = geometry.Rotate(b, i, math.Pi/2-rads)
-	rads = geometry.AngleBetween(b, a)
-	i = geometry.MustPoint(geometry.Intersection(b, c))
-	return a, geometry.Rotate(b, i, rads), geometry.Rotate(c, i, rads)
+) (geometry.Line, geometry.Line, geometry.Line, error) {
+	var none geometry.Line
+	if s.tol.AreParallel(a, b) {
+		i, err := s.tol.Intersection(b, c)
+		if err != nil {
+			return none, none, none, unstable(err, a, b, c)
+		}
+		rads := s.tol.AngleBetween(b, a) + math.Pi/2
+		b, c = geometry.Rotate(b, i, rads), geometry.Rotate(c, i, rads)
+	}
+	rads := s.tol.AngleBetween(b, c) + math.Pi/2
+	i, err := s.tol.Intersection(a, b)
+	if err != nil {
+		return none, none, none, unstable(err, a, b, c)
+	}
+	a = geometry.Rotate(a, i, rads)
+	b = geometry.Rotate(b, i, rads)
+	rads = s.tol.AngleBetween(b, a)
+	i, err = s.tol.Intersection(b, c)
+	if err != nil {
+		return none, none, none, unstable(err, a, b, c)
+	}
+	return a, geometry.Rotate(b, i, rads), geometry.Rotate(c, i, rads), nil
 }
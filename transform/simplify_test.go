@@ -0,0 +1,82 @@
+package transform
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jwowillo/viztransform/geometry"
+)
+
+// testPoints are mapped by Transformations to check they're the same.
+var testPoints = []geometry.Point{
+	{X: 0, Y: 0},
+	{X: 1, Y: 0},
+	{X: 0, Y: 1},
+	{X: 3, Y: -2},
+	{X: -5, Y: 7},
+}
+
+// testLine returns the geometry.Line through (ax ay) and (bx by).
+func testLine(ax, ay, bx, by geometry.Number) geometry.Line {
+	return geometry.MustLine(geometry.NewLineFromPoints(
+		geometry.Point{X: ax, Y: ay},
+		geometry.Point{X: bx, Y: by},
+	))
+}
+
+// checkSame fails the test if Transformations want and got don't map every
+// testPoint to the same geometry.Point.
+func checkSame(t *testing.T, want, got Transformation) {
+	t.Helper()
+	for _, p := range testPoints {
+		w, g := Apply(want, p), Apply(got, p)
+		if math.Abs(float64(w.X-g.X)) > 1e-6 ||
+			math.Abs(float64(w.Y-g.Y)) > 1e-6 {
+			t.Errorf("%v maps to %v but should map to %v", p, g, w)
+		}
+	}
+}
+
+// TestSimplify3 checks simplifying 3 line-reflections where at least one pair
+// of geometry.Lines intersects including when the first 2 are parallel.
+func TestSimplify3(t *testing.T) {
+	cases := []struct {
+		name    string
+		a, b, c geometry.Line
+	}{
+		{
+			"vertical parallel then diagonal",
+			testLine(0, 0, 0, 1), testLine(2, 0, 2, 1), testLine(0, 0, 1, 1),
+		},
+		{
+			"horizontal parallel then perpendicular",
+			testLine(0, 0, 1, 0), testLine(0, 3, 1, 3), testLine(5, 0, 5, 1),
+		},
+		{
+			"diagonal parallel then horizontal",
+			testLine(0, 0, 1, 1), testLine(0, 2, 1, 3), testLine(0, 4, 1, 4),
+		},
+		{
+			"diagonal parallel then steep",
+			testLine(0, 0, 1, 2), testLine(1, 0, 2, 2), testLine(3, 1, 4, -2),
+		},
+		{
+			"intersecting then parallel",
+			testLine(0, 0, 1, 1), testLine(0, 0, 1, 0), testLine(0, 3, 1, 3),
+		},
+		{
+			"all intersecting",
+			testLine(0, 0, 1, 2), testLine(1, 0, 1, 1), testLine(0, 4, 3, 5),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			tr := Transformation{c.a, c.b, c.c}
+			s := Simplify(tr)
+			if !IsSimplified(s) {
+				t.Errorf("%v isn't simplified", s)
+			}
+			checkSame(t, tr, s)
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package transform
 
 import (
+	"errors"
+	"math"
+
 	"github.com/jwowillo/viztransform/geometry"
 )
 
@@ -13,13 +16,49 @@ func Apply(t Transformation, p geometry.Point) geometry.Point {
 	return p
 }
 
+// ApplyE is Apply that returns an error instead of a geometry.Point that isn't
+// finite.
+//
+// t is simplified with SimplifyE first so rounding doesn't build up across
+// long Transformations. Returns an InstabilityError if SimplifyE does or if the
+// geometry.Point t moves p to isn't finite.
+func ApplyE(t Transformation, p geometry.Point) (geometry.Point, error) {
+	return ApplyWithinE(t, p, geometry.DefaultTolerance())
+}
+
+// ApplyWithinE is ApplyE where geometry.Lines are compared within
+// geometry.Tolerance tol.
+func ApplyWithinE(
+	t Transformation,
+	p geometry.Point,
+	tol geometry.Tolerance,
+) (geometry.Point, error) {
+	s, err := SimplifyWithinE(t, tol)
+	if err != nil {
+		return geometry.Point{}, err
+	}
+	q := Apply(s, p)
+	if !isFinite(q.X) || !isFinite(q.Y) {
+		return geometry.Point{}, &InstabilityError{Lines: s, Err: errNotFinite}
+	}
+	return q, nil
+}
+
+// errNotFinite is the cause of an InstabilityError when applying a
+// Transformation gives a geometry.Point that isn't finite.
+var errNotFinite = errors.New("transformed geometry.Point isn't finite")
+
+// isFinite returns true if geometry.Number x isn't infinite or NaN.
+func isFinite(x geometry.Number) bool {
+	return !math.IsInf(float64(x), 0) && !math.IsNaN(float64(x))
+}
+
 // apply a line-reflection to the geometry.Point as described in
 // TypeLineReflection.
+//
+// Doesn't compare any geometry.Numbers so works at any scale.
 func apply(l geometry.Line, p geometry.Point) geometry.Point {
-	i := geometry.MustPoint(geometry.Intersection(
-		l,
-		geometry.PerpendicularThroughPoint(l, p),
-	))
+	i := geometry.Project(l, p)
 	return geometry.Point{X: p.X + 2*(i.X-p.X), Y: p.Y + 2*(i.Y-p.Y)}
 }
 
@@ -33,6 +72,151 @@ func Compose(ts ...Transformation) Transformation {
 	return composed
 }
 
+// Inverse of Transformation t which undoes t.
+//
+// Is the line-reflections making up t in reverse order simplified since each
+// line-reflection undoes itself.
+func Inverse(t Transformation) Transformation {
+	return InverseWithin(t, geometry.DefaultTolerance())
+}
+
+// InverseWithin is Inverse where geometry.Lines are compared within
+// geometry.Tolerance tol.
+//
+// Panics with the InstabilityError InverseWithinE would return if simplifying
+// fails.
+func InverseWithin(t Transformation, tol geometry.Tolerance) Transformation {
+	inverse, err := InverseWithinE(t, tol)
+	if err != nil {
+		panic(err)
+	}
+	return inverse
+}
+
+// InverseE is Inverse that returns an error instead of panicking.
+//
+// Returns an InstabilityError if SimplifyE does for the reversed
+// line-reflections.
+func InverseE(t Transformation) (Transformation, error) {
+	return InverseWithinE(t, geometry.DefaultTolerance())
+}
+
+// InverseWithinE is InverseE where geometry.Lines are compared within
+// geometry.Tolerance tol.
+func InverseWithinE(
+	t Transformation,
+	tol geometry.Tolerance,
+) (Transformation, error) {
+	inverse := make(Transformation, len(t))
+	for i, l := range t {
+		inverse[len(t)-1-i] = l
+	}
+	return SimplifyWithinE(inverse, tol)
+}
+
+// Power of Transformation t which is t composed with itself n times.
+//
+// Negative n composes the Inverse of t instead and 0 gives NoTransformation.
+// The result is simplified and is built by repeatedly squaring t so large n
+// are quick.
+func Power(t Transformation, n int) Transformation {
+	return PowerWithin(t, n, geometry.DefaultTolerance())
+}
+
+// PowerWithin is Power where geometry.Lines are compared within
+// geometry.Tolerance tol.
+//
+// Panics with the InstabilityError PowerWithinE would return if simplifying
+// fails.
+func PowerWithin(t Transformation, n int, tol geometry.Tolerance) Transformation {
+	p, err := PowerWithinE(t, n, tol)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// PowerE is Power that returns an error instead of panicking.
+//
+// Returns an InstabilityError if SimplifyE does for any of the powers of t it's
+// built from.
+func PowerE(t Transformation, n int) (Transformation, error) {
+	return PowerWithinE(t, n, geometry.DefaultTolerance())
+}
+
+// PowerWithinE is PowerE where geometry.Lines are compared within
+// geometry.Tolerance tol.
+func PowerWithinE(
+	t Transformation,
+	n int,
+	tol geometry.Tolerance,
+) (Transformation, error) {
+	var err error
+	if n < 0 {
+		t, err = InverseWithinE(t, tol)
+		n = -n
+	} else {
+		t, err = SimplifyWithinE(t, tol)
+	}
+	if err != nil {
+		return nil, err
+	}
+	p := NoTransformation()
+	for ; n > 0; n /= 2 {
+		if n%2 == 1 {
+			if p, err = SimplifyWithinE(Compose(p, t), tol); err != nil {
+				return nil, err
+			}
+		}
+		if n > 1 {
+			if t, err = SimplifyWithinE(Compose(t, t), tol); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return p, nil
+}
+
+// Conjugate of Transformation a by Transformation b which undoes b, does a,
+// and then does b.
+//
+// The result does to the geometry.Points moved by b what a does to the
+// original geometry.Points. For example, conjugating a rotation around a
+// geometry.Point by a translation gives the same rotation around the
+// translated geometry.Point. The result isn't simplified.
+func Conjugate(a, b Transformation) Transformation {
+	return ConjugateWithin(a, b, geometry.DefaultTolerance())
+}
+
+// ConjugateWithin is Conjugate where geometry.Lines are compared within
+// geometry.Tolerance tol.
+//
+// Panics with the InstabilityError ConjugateWithinE would return if
+// simplifying fails.
+func ConjugateWithin(a, b Transformation, tol geometry.Tolerance) Transformation {
+	return Compose(InverseWithin(b, tol), a, b)
+}
+
+// ConjugateE is Conjugate that returns an error instead of panicking.
+//
+// Returns an InstabilityError if InverseE does for b.
+func ConjugateE(a, b Transformation) (Transformation, error) {
+	return ConjugateWithinE(a, b, geometry.DefaultTolerance())
+}
+
+// ConjugateWithinE is ConjugateE where geometry.Lines are compared within
+// geometry.Tolerance tol.
+func ConjugateWithinE(
+	a, b Transformation,
+	tol geometry.Tolerance,
+) (Transformation, error) {
+	inverse, err := InverseWithinE(b, tol)
+	if err != nil {
+		return nil, err
+	}
+	return Compose(inverse, a, b), nil
+}
+
 // NoTransformation is a Transformation-constructor that creates a
 // Transformation with TypeNoTransformation that does nothing to
 // geometry.Points.
@@ -53,16 +237,27 @@ func LineReflection(l geometry.Line) Transformation {
 //
 // Returns NoTransformation() if v is length 0.
 func Translation(v geometry.Vector) Transformation {
+	return TranslationWithin(v, geometry.DefaultTolerance())
+}
+
+// TranslationWithin is Translation where v is length 0 if it's within
+// geometry.Tolerance tol of it.
+func TranslationWithin(
+	v geometry.Vector,
+	tol geometry.Tolerance,
+) Transformation {
 	length := geometry.Length(v)
-	if geometry.IsZero(length) {
+	if tol.IsZero(length) {
 		return NoTransformation()
 	}
-	v = geometry.MustVector(geometry.Scale(v, length/2))
-	a, b := geometry.Point{X: 0, Y: 0}, geometry.Point{X: v.I, Y: v.J}
-	l := geometry.MustLine(geometry.NewLineFromPoints(a, b))
+	// The geometry.Lines are perpendicular to v and half of v apart. Their
+	// slope is found from v scaled to length 1 so the Points making them up
+	// are never too close no matter how short v is.
+	rise, run := v.I/length, -v.J/length
+	a, b := geometry.Point{X: 0, Y: 0}, geometry.Point{X: v.I / 2, Y: v.J / 2}
 	return Transformation{
-		geometry.PerpendicularThroughPoint(l, a),
-		geometry.PerpendicularThroughPoint(l, b),
+		geometry.MustLine(geometry.NewLineFromPointAndSlope(a, rise, run)),
+		geometry.MustLine(geometry.NewLineFromPointAndSlope(b, rise, run)),
 	}
 }
 
@@ -72,10 +267,20 @@ func Translation(v geometry.Vector) Transformation {
 //
 // Returns NoTransformation() if rads is 0.
 func Rotation(p geometry.Point, rads geometry.Angle) Transformation {
-	if geometry.IsZero(geometry.Number(rads)) {
+	return RotationWithin(p, rads, geometry.DefaultTolerance())
+}
+
+// RotationWithin is Rotation where rads is 0 if it's within
+// geometry.Tolerance tol of it.
+func RotationWithin(
+	p geometry.Point,
+	rads geometry.Angle,
+	tol geometry.Tolerance,
+) Transformation {
+	if tol.IsZero(geometry.Number(rads)) {
 		return NoTransformation()
 	}
-	a := geometry.MustLine(geometry.NewLineFromPoints(
+	a := geometry.MustLine(tol.NewLineFromPoints(
 		p,
 		geometry.Point{X: p.X + 1, Y: p.Y},
 	))
@@ -94,6 +299,16 @@ func Rotation(p geometry.Point, rads geometry.Angle) Transformation {
 // Has this interface because a Transformation can still be determined if v is
 // length 0.
 func GlideReflection(ref geometry.Line, v geometry.Vector) Transformation {
+	return GlideReflectionWithin(ref, v, geometry.DefaultTolerance())
+}
+
+// GlideReflectionWithin is GlideReflection where the projection of v is length
+// 0 if it's within geometry.Tolerance tol of it.
+func GlideReflectionWithin(
+	ref geometry.Line,
+	v geometry.Vector,
+	tol geometry.Tolerance,
+) Transformation {
 	a := geometry.PerpendicularThroughPoint(ref, geometry.Point{X: 0, Y: 0})
 	b := geometry.PerpendicularThroughPoint(
 		ref,
@@ -101,6 +316,6 @@ func GlideReflection(ref geometry.Line, v geometry.Vector) Transformation {
 	)
 	return Compose(
 		LineReflection(ref),
-		Translation(geometry.ShortestVector(a, b)),
+		TranslationWithin(tol.ShortestVector(a, b), tol),
 	)
 }
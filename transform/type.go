@@ -5,6 +5,7 @@ package transform
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/jwowillo/viztransform/geometry"
 )
@@ -70,104 +71,71 @@ type Transformation []geometry.Line
 // 	Translation(geometry.Vector)
 // 	Rotation(geometry.Point, geometry.Number)
 // 	GlideReflection(geometry.Line, geometry.Vector)
+//
+// Looks like 'Compose(LineReflection(geometry.Line), ...)' with each
+// line-reflection making up t instead if t's geometry.Lines are so close to
+// being degenerate that DescribeE fails.
 func (t Transformation) String() string {
-	t = Simplify(t)
-	var out string
-	switch TypeOf(t) {
-	case TypeNoTransformation:
-		out = stringNoTransformation()
-	case TypeLineReflection:
-		out = stringLineReflection(t[0])
-	case TypeTranslation:
-		out = stringTranslation(t[0], t[1])
-	case TypeRotation:
-		out = stringRotation(t[0], t[1])
-	case TypeGlideReflection:
-		out = stringGlideReflection(t[0], t[1], t[2])
+	p, err := DescribeE(t)
+	if err != nil {
+		parts := make([]string, len(t))
+		for i, l := range t {
+			parts[i] = LineReflectionParams{Line: l}.String()
+		}
+		return fmt.Sprintf("Compose(%s)", strings.Join(parts, ", "))
 	}
-	return out
-}
-
-// stringNoTransformation returns the string-representation of a Transformation
-// with TypeNoTransformation.
-//
-// Looks like 'NoTransformation()'.
-func stringNoTransformation() string {
-	return "NoTransformation()"
-}
-
-// stringLineReflection returns the string-representation of the Transformation
-// with TypeLineReflection over geometry.Line l.
-//
-// Looks like 'LineReflection(geometry.Line)' where geometry.Line is the passed
-// geometry.Line.
-func stringLineReflection(l geometry.Line) string {
-	return fmt.Sprintf("LineReflection(%s)", l)
+	return p.String()
 }
 
-// stringTranslation returns the string-representation of the Transformation
-// with TypeTranslation created by parallel geometry.Lines a and b.
-//
-// Looks like 'Translation(geometry.Vector)' where geometry.Vector is the
-// shortest geometry.Vector from a to b scaled by 2 since a translation from 2
-// line-reflections translates by 2 times the shortest distance from the first
-// geometry.Line to the second.
-func stringTranslation(a, b geometry.Line) string {
-	v := geometry.ShortestVector(a, b)
-	v = geometry.MustVector(geometry.Scale(v, 2*geometry.Length(v)))
-	return fmt.Sprintf("Translation(%s)", v)
-}
+// Type of a Transformation in terms of how it transforms geometry.Points.
+type Type int
 
-// stringRotation returns the string-representation of the Transformation with
-// TypeRotation created by intersecting geometry.Lines a and b.
-//
-// Looks like 'Rotation(geometry.Point, geometry.Angle)' where geometry.Point
-// is the intersection of a and b and geometry.Angle is 2 times the angle from
-// a to b mod 2pi since a rotation from 2 line-reflections rotates 2 times the
-// angle from the first geometry.Line to the second.
-func stringRotation(a, b geometry.Line) string {
-	return fmt.Sprintf(
-		"Rotation(%s, %s)",
-		geometry.MustPoint(geometry.Intersection(a, b)),
-		2*geometry.AngleBetween(a, b),
-	)
+// TypeOf a Transformation from the defined Transformation-Types.
+func TypeOf(t Transformation) Type {
+	return TypeOfWithin(t, geometry.DefaultTolerance())
 }
 
-// stringGlideReflection returns the string-representation of the Transformation
-// with TypeGlieReflection created by geometry.Lines a, b, and c with a and be
-// parallel and b and c perpendicular or a and b perpendicular or b and c
-// parallel.
+// TypeOfWithin is TypeOf where geometry.Lines are compared within
+// geometry.Tolerance tol.
 //
-// Looks like 'GlideReflection(geometry.Line, geometry.Vector)' where
-// geometry.Line is the geometry.Line the reflection is happening over and the
-// projection of the geometry.Vector onto the geometry.Line is the
-// geometry.Vector of translation.
-func stringGlideReflection(a, b, c geometry.Line) string {
-	if geometry.AreParallel(b, c) {
-		a, b, c = b, c, a
+// Panics with the InstabilityError TypeOfWithinE would return if simplifying
+// fails.
+func TypeOfWithin(t Transformation, tol geometry.Tolerance) Type {
+	typ, err := TypeOfWithinE(t, tol)
+	if err != nil {
+		panic(err)
 	}
-	v := geometry.ShortestVector(a, b)
-	return fmt.Sprintf(
-		"GlideReflection(%s, %s)",
-		c,
-		geometry.MustVector(geometry.Scale(v, 2*geometry.Length(v))),
-	)
+	return typ
 }
 
-// Type of a Transformation in terms of how it transforms geometry.Points.
-type Type int
-
-// TypeOf a Transformation from the defined Transformation-Types.
-func TypeOf(t Transformation) Type {
-	t = Simplify(t)
+// typeOf simplified Transformation t where geometry.Lines are compared within
+// geometry.Tolerance tol.
+func typeOf(t Transformation, tol geometry.Tolerance) Type {
 	if len(t) == 0 {
 		return TypeNoTransformation
 	} else if len(t) == 1 {
 		return TypeLineReflection
-	} else if len(t) == 2 && geometry.AreParallel(t[0], t[1]) {
+	} else if len(t) == 2 && tol.AreParallel(t[0], t[1]) {
 		return TypeTranslation
 	} else if len(t) == 2 {
 		return TypeRotation
 	}
 	return TypeGlideReflection
 }
+
+// TypeOfE is TypeOf that returns an error instead of panicking.
+//
+// Returns an InstabilityError if SimplifyE does.
+func TypeOfE(t Transformation) (Type, error) {
+	return TypeOfWithinE(t, geometry.DefaultTolerance())
+}
+
+// TypeOfWithinE is TypeOfE where geometry.Lines are compared within
+// geometry.Tolerance tol.
+func TypeOfWithinE(t Transformation, tol geometry.Tolerance) (Type, error) {
+	s, err := SimplifyWithinE(t, tol)
+	if err != nil {
+		return TypeNoTransformation, err
+	}
+	return typeOf(s, tol), nil
+}
@@ -0,0 +1,207 @@
+package viz
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/jwowillo/viztransform/geometry"
+	"github.com/jwowillo/viztransform/transform"
+)
+
+const (
+	// stepFrames is how many frames each motion is interpolated over.
+	stepFrames = 12
+	// stepDelay is how long each interpolated frame is shown in 100ths of a
+	// second.
+	stepDelay = 5
+	// holdDelay is how long the frame finishing each motion is shown in
+	// 100ths of a second.
+	holdDelay = 60
+	// endDelay is how long the last frame is shown in 100ths of a second.
+	endDelay = 200
+)
+
+// frame is a panel shown for delay 100ths of a second.
+type frame struct {
+	p     panel
+	delay int
+}
+
+// newAnimation creates the frames animating Transformation t with simplified
+// form s.
+//
+// The figure is first moved through each line-reflection making up t in order
+// and then moved from where it started by s. Every frame shows the same region
+// of the plane. geometry.Lines are compared within geometry.Tolerance tol.
+func newAnimation(
+	t, s transform.Transformation,
+	tol geometry.Tolerance,
+) []frame {
+	anchor, size := placeFigure(s, tol)
+	before := placedFigure(anchor, size)
+	region := animationRegion(t, s, anchor, before, tol)
+	frames := []frame{{
+		p:     reflectionFrame(t, -1, region, before, before),
+		delay: holdDelay,
+	}}
+	current := before
+	for i, l := range t {
+		for k := 1; k <= stepFrames; k++ {
+			moving := fold(l, current, float64(k)/stepFrames)
+			frames = append(frames, frame{
+				p:     reflectionFrame(t, i, region, before, moving),
+				delay: stepDelay,
+			})
+		}
+		frames[len(frames)-1].delay = holdDelay
+		current = transform.ApplyPolygon(transform.LineReflection(l), current)
+	}
+	for k := 0; k <= stepFrames; k++ {
+		moving := motion(s, before, float64(k)/stepFrames, tol)
+		f := frame{
+			p: simplifiedFrame(
+				s, anchor,
+				region, before, moving,
+				tol,
+			),
+			delay: stepDelay,
+		}
+		if k == stepFrames {
+			f.p.addSample(anchor, transform.Apply(s, anchor))
+			f.delay = endDelay
+		}
+		f.p.frame()
+		frames = append(frames, f)
+	}
+	return frames
+}
+
+// animationRegion returns the bottom-left and top-right corners of a region
+// that fits every frame animating Transformation t with simplified form s
+// acting on the figure with vertices before centered at anchor.
+// geometry.Lines are compared within geometry.Tolerance tol.
+func animationRegion(
+	t, s transform.Transformation,
+	anchor geometry.Point,
+	before []geometry.Point,
+	tol geometry.Tolerance,
+) []geometry.Point {
+	var p panel
+	p.include(before...)
+	current := before
+	for _, l := range t {
+		current = transform.ApplyPolygon(transform.LineReflection(l), current)
+		p.include(current...)
+	}
+	p.include(transform.ApplyPolygon(s, before)...)
+	p.addReflections(t, -1)
+	p.addParts(s, anchor, tol)
+	return []geometry.Point{p.min, p.max}
+}
+
+// reflectionFrame returns a panel fitting region that shows the line-reflection
+// at index active of Transformation t moving the figure with vertices before to
+// the figure with vertices moving.
+//
+// No line-reflection is shown as active if active is out of range.
+func reflectionFrame(
+	t transform.Transformation,
+	active int,
+	region, before, moving []geometry.Point,
+) panel {
+	p := panel{title: fmt.Sprintf("%d line-reflections", len(t))}
+	if active >= 0 {
+		p.title = fmt.Sprintf("Line-reflection %d of %d", active+1, len(t))
+	}
+	p.include(region...)
+	p.addReflections(t, active)
+	p.add(polygon{points: before, color: colorFigure})
+	p.add(polygon{points: moving, color: colorImage})
+	p.frame()
+	return p
+}
+
+// simplifiedFrame returns a panel fitting region that shows the simplified
+// Transformation s moving the figure with vertices before and centered at
+// anchor to the figure with vertices moving.
+//
+// The panel isn't framed so more shapes can be added to it. geometry.Lines are
+// compared within geometry.Tolerance tol.
+func simplifiedFrame(
+	s transform.Transformation,
+	anchor geometry.Point,
+	region, before, moving []geometry.Point,
+	tol geometry.Tolerance,
+) panel {
+	p := panel{title: "Simplified: " + s.String()}
+	p.include(region...)
+	p.addParts(s, anchor, tol)
+	p.add(polygon{points: before, color: colorFigure})
+	p.add(polygon{points: moving, color: colorImage})
+	return p
+}
+
+// motion returns geometry.Points ps moved part of the way by the simplified
+// Transformation s where u is the fraction of the way from 0 to 1.
+//
+// Rotations turn the shortest way around their center, translations slide
+// along their geometry.Vector, and reflections fold across their
+// geometry.Line. geometry.Lines are compared within geometry.Tolerance tol.
+func motion(
+	s transform.Transformation,
+	ps []geometry.Point,
+	u float64,
+	tol geometry.Tolerance,
+) []geometry.Point {
+	switch d := transform.DescribeWithin(s, tol).(type) {
+	case transform.LineReflectionParams:
+		return fold(d.Line, ps, u)
+	case transform.TranslationParams:
+		return slide(d.Vector, ps, u)
+	case transform.RotationParams:
+		sweep := math.Remainder(float64(d.Angle), 2*math.Pi) * u
+		return turn(d.Center, ps, sweep)
+	case transform.GlideParams:
+		return slide(d.Vector, fold(d.Axis, ps, u), u)
+	}
+	return ps
+}
+
+// fold geometry.Points ps part of the way across geometry.Line l where u is the
+// fraction of the way from 0 to 1.
+func fold(l geometry.Line, ps []geometry.Point, u float64) []geometry.Point {
+	reflected := transform.ApplyPolygon(transform.LineReflection(l), ps)
+	out := make([]geometry.Point, len(ps))
+	for i, p := range ps {
+		out[i] = geometry.Point{
+			X: p.X + geometry.Number(u)*(reflected[i].X-p.X),
+			Y: p.Y + geometry.Number(u)*(reflected[i].Y-p.Y),
+		}
+	}
+	return out
+}
+
+// slide geometry.Points ps part of the way along geometry.Vector v where u is
+// the fraction of the way from 0 to 1.
+func slide(v geometry.Vector, ps []geometry.Point, u float64) []geometry.Point {
+	out := make([]geometry.Point, len(ps))
+	for i, p := range ps {
+		out[i] = geometry.Point{
+			X: p.X + geometry.Number(u)*v.I,
+			Y: p.Y + geometry.Number(u)*v.J,
+		}
+	}
+	return out
+}
+
+// turn geometry.Points ps counter-clockwise around geometry.Point c by rads
+// radians.
+func turn(c geometry.Point, ps []geometry.Point, rads float64) []geometry.Point {
+	cos, sin := geometry.Number(math.Cos(rads)), geometry.Number(math.Sin(rads))
+	out := make([]geometry.Point, len(ps))
+	for i, p := range ps {
+		x, y := p.X-c.X, p.Y-c.Y
+		out[i] = geometry.Point{X: c.X + x*cos - y*sin, Y: c.Y + x*sin + y*cos}
+	}
+	return out
+}
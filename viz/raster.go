@@ -0,0 +1,286 @@
+package viz
+
+import (
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"math"
+	"sort"
+
+	"github.com/jwowillo/viztransform/geometry"
+)
+
+// colorSeparator is the color of the line between panels.
+var colorSeparator = color.NRGBA{R: 0xCC, G: 0xCC, B: 0xCC, A: 0xFF}
+
+// labelScale is how many pixels wide and tall each pixel of a glyph is drawn.
+const labelScale = 2
+
+// canvas draws a panel onto part of an image.RGBA.
+type canvas struct {
+	img *image.RGBA
+	p   panel
+	// left is the x-coordinate of the panel's left edge in the image.
+	left int
+}
+
+// rasterize scene s into an image.RGBA with each panel drawn side by side on a
+// white background.
+func rasterize(s scene) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, panelSize*len(s), panelSize))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	for i, p := range s {
+		c := canvas{img: img, p: p, left: i * panelSize}
+		for _, sh := range p.shapes {
+			c.draw(sh)
+		}
+		c.title(p.title)
+		if i > 0 {
+			c.stroke(
+				[]pixel{
+					{x: float64(c.left), y: 0},
+					{x: float64(c.left), y: panelSize},
+				},
+				colorSeparator, 1, false,
+			)
+		}
+	}
+	return img
+}
+
+// draw shape s onto the canvas.
+func (c canvas) draw(s shape) {
+	switch s := s.(type) {
+	case path:
+		ps := c.pixels(s.points)
+		c.stroke(ps, s.color, s.width, s.dashed)
+		if s.arrow && len(ps) > 1 {
+			c.fill(head(ps[len(ps)-2], ps[len(ps)-1]), s.color)
+		}
+	case polygon:
+		ps := c.pixels(s.points)
+		fill := s.color
+		fill.A = fillAlpha
+		c.fill(ps, fill)
+		c.stroke(append(ps, ps[0]), s.color, 2, false)
+	case dot:
+		x := c.pixel(s.center)
+		c.disc(x.x, x.y, s.radius, s.color)
+	case label:
+		x := c.p.place(
+			s.at,
+			float64(textWidth(s.text, labelScale)),
+			glyphHeight*labelScale,
+		)
+		c.text(x.x+float64(c.left), x.y, s.text, s.color, labelScale)
+	}
+}
+
+// title draws text t in the top-left corner of the canvas.
+//
+// The text is drawn smaller if it wouldn't fit otherwise.
+func (c canvas) title(t string) {
+	scale := labelScale
+	if textWidth(t, scale)+2*titleMargin > panelSize {
+		scale = 1
+	}
+	c.text(
+		float64(c.left+titleMargin),
+		float64(titleMargin+glyphHeight*scale),
+		t, colorText, scale,
+	)
+}
+
+// text draws string t in the given color with its bottom-left corner at x and
+// y with every glyph pixel drawn as a square with sides scale pixels long.
+func (c canvas) text(x, y float64, t string, col color.NRGBA, scale int) {
+	left, bottom := int(math.Round(x)), int(math.Round(y))
+	top := bottom - glyphHeight*scale
+	for _, r := range t {
+		g := glyphs[r]
+		for row, bits := range g {
+			for column := 0; column < glyphWidth; column++ {
+				if bits&(1<<uint(glyphWidth-1-column)) == 0 {
+					continue
+				}
+				for i := 0; i < scale; i++ {
+					for j := 0; j < scale; j++ {
+						c.set(
+							left+column*scale+i,
+							top+row*scale+j,
+							col,
+						)
+					}
+				}
+			}
+		}
+		left += (glyphWidth + 1) * scale
+	}
+}
+
+// textWidth returns how many pixels wide string t is when drawn with the given
+// scale.
+func textWidth(t string, scale int) int {
+	return len([]rune(t)) * (glyphWidth + 1) * scale
+}
+
+// pixel returns where geometry.Point x is on the canvas.
+func (c canvas) pixel(x geometry.Point) pixel {
+	p := c.p.pixel(x)
+	p.x += float64(c.left)
+	return p
+}
+
+// pixels returns where all geometry.Points xs are on the canvas.
+func (c canvas) pixels(xs []geometry.Point) []pixel {
+	out := c.p.pixels(xs)
+	for i := range out {
+		out[i].x += float64(c.left)
+	}
+	return out
+}
+
+// stroke the connected pixels ps with a stroke of the given color and width.
+//
+// Every other dashLength and gapLength pixels are skipped if dashed is true.
+func (c canvas) stroke(ps []pixel, col color.NRGBA, width float64, dashed bool) {
+	var travelled float64
+	for i := 1; i < len(ps); i++ {
+		a, b := ps[i-1], ps[i]
+		l := math.Hypot(b.x-a.x, b.y-a.y)
+		steps := int(math.Ceil(l * 2))
+		for j := 0; j <= steps; j++ {
+			t := 0.0
+			if steps > 0 {
+				t = float64(j) / float64(steps)
+			}
+			d := math.Mod(travelled+t*l, dashLength+gapLength)
+			if dashed && d > dashLength {
+				continue
+			}
+			c.disc(a.x+t*(b.x-a.x), a.y+t*(b.y-a.y), width/2, col)
+		}
+		travelled += l
+	}
+}
+
+// fill the polygon with vertices ps with the given color using the even-odd
+// rule.
+func (c canvas) fill(ps []pixel, col color.NRGBA) {
+	for y := 0; y < panelSize; y++ {
+		sy := float64(y) + 0.5
+		var xs []float64
+		for i := range ps {
+			a, b := ps[i], ps[(i+1)%len(ps)]
+			if (a.y <= sy) == (b.y <= sy) {
+				continue
+			}
+			xs = append(xs, a.x+(sy-a.y)/(b.y-a.y)*(b.x-a.x))
+		}
+		sort.Float64s(xs)
+		for i := 0; i+1 < len(xs); i += 2 {
+			start := int(math.Ceil(xs[i] - 0.5))
+			end := int(math.Floor(xs[i+1] - 0.5))
+			for x := start; x <= end; x++ {
+				c.set(x, y, col)
+			}
+		}
+	}
+}
+
+// disc fills a circle centered at x and y with radius r in the given color.
+//
+// Only the pixel containing the center is filled if r is less than 1.
+func (c canvas) disc(x, y, r float64, col color.NRGBA) {
+	if r < 1 {
+		c.set(int(math.Floor(x)), int(math.Floor(y)), col)
+		return
+	}
+	for i := int(math.Floor(x - r)); i <= int(math.Ceil(x+r)); i++ {
+		for j := int(math.Floor(y - r)); j <= int(math.Ceil(y+r)); j++ {
+			dx, dy := float64(i)+0.5-x, float64(j)+0.5-y
+			if dx*dx+dy*dy <= r*r {
+				c.set(i, j, col)
+			}
+		}
+	}
+}
+
+// set blends the pixel at x and y with the given color if the pixel is in the
+// canvas's panel.
+func (c canvas) set(x, y int, col color.NRGBA) {
+	if x < c.left || x >= c.left+panelSize || y < 0 || y >= panelSize {
+		return
+	}
+	i := c.img.PixOffset(x, y)
+	a := uint32(col.A)
+	for k, v := range [3]uint8{col.R, col.G, col.B} {
+		d := uint32(c.img.Pix[i+k])
+		c.img.Pix[i+k] = uint8((uint32(v)*a + d*(0xFF-a)) / 0xFF)
+	}
+	c.img.Pix[i+3] = 0xFF
+}
+
+// paletted converts image.RGBA img to an image.Paletted using animationPalette.
+//
+// Each color's closest color in the palette is only searched for once since
+// images have few distinct colors.
+func paletted(img *image.RGBA) *image.Paletted {
+	p := image.NewPaletted(img.Bounds(), animationPalette)
+	indices := make(map[color.RGBA]uint8)
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			c := img.RGBAAt(x, y)
+			i, ok := indices[c]
+			if !ok {
+				i = uint8(p.Palette.Index(c))
+				indices[c] = i
+			}
+			p.SetColorIndex(x, y, i)
+		}
+	}
+	return p
+}
+
+// animationPalette has every color a scene is drawn with, those colors filled
+// over white and over each other, and web-safe colors for anything else.
+var animationPalette = func() color.Palette {
+	base := []color.NRGBA{
+		colorFigure, colorImage, colorMirror, colorReflection,
+		colorMotion, colorSeparator, colorText,
+	}
+	p := color.Palette{color.White}
+	for _, c := range base {
+		p = append(p, c)
+	}
+	for _, a := range base[:2] {
+		fa := blend(color.NRGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF}, a)
+		p = append(p, fa)
+		for _, b := range base[:2] {
+			if a != b {
+				p = append(p, blend(fa, b))
+			}
+		}
+	}
+	for _, c := range palette.WebSafe {
+		if len(p) == 256 {
+			break
+		}
+		p = append(p, c)
+	}
+	return p
+}()
+
+// blend returns color c filled over color under with fillAlpha opacity.
+func blend(under, c color.NRGBA) color.NRGBA {
+	mix := func(u, v uint8) uint8 {
+		return uint8((uint32(v)*fillAlpha + uint32(u)*(0xFF-fillAlpha)) / 0xFF)
+	}
+	return color.NRGBA{
+		R: mix(under.R, c.R),
+		G: mix(under.G, c.G),
+		B: mix(under.B, c.B),
+		A: 0xFF,
+	}
+}
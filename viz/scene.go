@@ -0,0 +1,523 @@
+package viz
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/jwowillo/viztransform/geometry"
+	"github.com/jwowillo/viztransform/transform"
+)
+
+// Colors used to draw the parts of a scene.
+var (
+	// colorFigure is the color of the figure before it is transformed.
+	colorFigure = color.NRGBA{R: 0x1F, G: 0x77, B: 0xB4, A: 0xFF}
+	// colorImage is the color of the figure after it is transformed.
+	colorImage = color.NRGBA{R: 0xD6, G: 0x27, B: 0x28, A: 0xFF}
+	// colorMirror is the color of geometry.Lines reflected across.
+	colorMirror = color.NRGBA{A: 0xFF}
+	// colorReflection is the color of the individual line-reflections
+	// making up a Transformation that isn't simplified.
+	colorReflection = color.NRGBA{R: 0x88, G: 0x88, B: 0x88, A: 0xFF}
+	// colorMotion is the color of vectors, arcs, and centers that show how
+	// the figure moves.
+	colorMotion = color.NRGBA{R: 0x2C, G: 0xA0, B: 0x2C, A: 0xFF}
+)
+
+// figure is a sample shape with no symmetries so that every transformation of
+// it, including reflections, can be seen.
+//
+// The shape is an 'F' with height 1 centered on the origin.
+var figure = []geometry.Point{
+	{X: -0.3, Y: -0.5}, {X: -0.1, Y: -0.5}, {X: -0.1, Y: -0.1},
+	{X: 0.2, Y: -0.1}, {X: 0.2, Y: 0.1}, {X: -0.1, Y: 0.1},
+	{X: -0.1, Y: 0.3}, {X: 0.3, Y: 0.3}, {X: 0.3, Y: 0.5},
+	{X: -0.3, Y: 0.5},
+}
+
+// colorText is the color of panel titles.
+var colorText = color.NRGBA{A: 0xFF}
+
+const (
+	// panelSize is the width and height in pixels of each panel.
+	panelSize = 500
+	// fillAlpha is the opacity polygons are filled with.
+	fillAlpha = 0x50
+	// dashLength and gapLength are the pixel lengths of the drawn and
+	// skipped parts of dashed paths.
+	dashLength, gapLength = 8, 6
+	// headLength and headWidth are the pixel sizes of arrow heads.
+	headLength, headWidth = 12, 10
+	// labelOffset is how many pixels labels are moved away from the
+	// geometry.Point they label.
+	labelOffset = 6
+	// titleMargin is how many pixels titles are from the top-left corner of
+	// their panel.
+	titleMargin = 8
+)
+
+// scene is a list of panels that are drawn side by side.
+//
+// Every backend draws the same scene so they all show the same thing.
+type scene []panel
+
+// panel is a titled square region of the plane with shapes drawn in it.
+type panel struct {
+	title string
+	// min and max are the bottom-left and top-right corners of the region.
+	min, max geometry.Point
+	shapes   []shape
+	// sized is true once a geometry.Point has been included.
+	sized bool
+}
+
+// shape is something drawn in a panel.
+//
+// Is one of path, polygon, dot, label, or line.
+type shape interface{}
+
+// path is a stroked list of geometry.Points.
+type path struct {
+	points []geometry.Point
+	color  color.NRGBA
+	// width of the stroke in pixels.
+	width float64
+	// dashed paths are drawn with gaps.
+	dashed bool
+	// arrow paths have a head drawn at their last geometry.Point.
+	arrow bool
+}
+
+// polygon is a filled and stroked closed list of geometry.Points.
+type polygon struct {
+	points []geometry.Point
+	color  color.NRGBA
+}
+
+// line is a geometry.Line drawn across the whole panel.
+//
+// lines are turned into paths and labels when the panel is framed.
+type line struct {
+	l     geometry.Line
+	color color.NRGBA
+	// width of the stroke in pixels.
+	width  float64
+	dashed bool
+	// name is drawn next to the line if it isn't empty.
+	name string
+}
+
+// label is text drawn next to a geometry.Point.
+type label struct {
+	at    geometry.Point
+	text  string
+	color color.NRGBA
+}
+
+// dot is a filled circle with a radius in pixels centered on a geometry.Point.
+type dot struct {
+	center geometry.Point
+	color  color.NRGBA
+	radius float64
+}
+
+// newScene creates the scene for Transformation t with simplified form s.
+//
+// The scene has a single panel showing t if t is simplified and a panel
+// showing the line-reflections making up t followed by a panel showing s
+// otherwise. geometry.Lines are compared within geometry.Tolerance tol.
+func newScene(t, s transform.Transformation, tol geometry.Tolerance) scene {
+	anchor, size := placeFigure(s, tol)
+	if transform.IsSimplifiedWithin(t, tol) {
+		return scene{simplifiedPanel(s, anchor, size, tol)}
+	}
+	return scene{
+		reflectionsPanel(t, anchor, size),
+		simplifiedPanel(s, anchor, size, tol),
+	}
+}
+
+// placeFigure returns where the figure should be centered to show the
+// simplified Transformation s well along with how big the figure should be
+// when geometry.Lines are compared within geometry.Tolerance tol.
+func placeFigure(
+	s transform.Transformation,
+	tol geometry.Tolerance,
+) (geometry.Point, geometry.Number) {
+	origin := geometry.Point{X: 0, Y: 0}
+	switch d := transform.DescribeWithin(s, tol).(type) {
+	case transform.LineReflectionParams:
+		return offset(d.Line, geometry.Project(d.Line, origin), 1), 1
+	case transform.TranslationParams:
+		return origin, fitSize(d.Vector, tol)
+	case transform.RotationParams:
+		return geometry.Point{X: d.Center.X + 2, Y: d.Center.Y}, 1
+	case transform.GlideParams:
+		size := fitSize(d.Vector, tol)
+		return offset(d.Axis, geometry.Project(d.Axis, origin), size), size
+	}
+	return origin, 1
+}
+
+// fitSize returns a figure size that keeps the figure from covering up its
+// image after being moved by geometry.Vector v where lengths within
+// geometry.Tolerance tol of 0 are 0.
+func fitSize(v geometry.Vector, tol geometry.Tolerance) geometry.Number {
+	l := geometry.Length(v)
+	if tol.IsZero(l) {
+		return 1
+	}
+	return l / 2
+}
+
+// reflectionsPanel shows every line-reflection in Transformation t along with
+// the figure of the given size centered at anchor before and after t.
+func reflectionsPanel(
+	t transform.Transformation,
+	anchor geometry.Point,
+	size geometry.Number,
+) panel {
+	before := placedFigure(anchor, size)
+	after := transform.ApplyPolygon(t, before)
+	p := panel{title: fmt.Sprintf("%d line-reflections", len(t))}
+	p.include(before...)
+	p.include(after...)
+	p.addReflections(t, -1)
+	p.add(polygon{points: before, color: colorFigure})
+	p.add(polygon{points: after, color: colorImage})
+	p.addSample(anchor, transform.Apply(t, anchor))
+	p.frame()
+	return p
+}
+
+// simplifiedPanel shows the simplified Transformation s along with the figure
+// of the given size centered at anchor before and after s where geometry.Lines
+// are compared within geometry.Tolerance tol.
+func simplifiedPanel(
+	s transform.Transformation,
+	anchor geometry.Point,
+	size geometry.Number,
+	tol geometry.Tolerance,
+) panel {
+	before := placedFigure(anchor, size)
+	after := transform.ApplyPolygon(s, before)
+	p := panel{title: s.String()}
+	p.include(before...)
+	p.include(after...)
+	p.addParts(s, anchor, tol)
+	p.add(polygon{points: before, color: colorFigure})
+	p.add(polygon{points: after, color: colorImage})
+	p.addSample(anchor, transform.Apply(s, anchor))
+	p.frame()
+	return p
+}
+
+// addReflections adds every line-reflection in Transformation t to the panel as
+// numbered lines.
+//
+// The line-reflection at index active is drawn like a geometry.Line being
+// reflected across instead of like the others. No line-reflection is if active
+// is out of range.
+//
+// The panel's region grows to include part of every line-reflection.
+func (p *panel) addReflections(t transform.Transformation, active int) {
+	for _, l := range t {
+		p.include(geometry.Project(l, p.center()))
+	}
+	for i, l := range t {
+		p.add(line{
+			l:      l,
+			color:  colorReflection,
+			width:  2,
+			dashed: i != active,
+			name:   fmt.Sprint(i + 1),
+		})
+		if i == active {
+			p.add(line{l: l, color: colorMirror, width: 2})
+		}
+	}
+}
+
+// addParts adds the parts defining the simplified Transformation s to the
+// panel.
+//
+// The parts are shown acting on geometry.Point anchor. The panel's region grows
+// to include the parts. geometry.Lines are compared within geometry.Tolerance
+// tol.
+func (p *panel) addParts(
+	s transform.Transformation,
+	anchor geometry.Point,
+	tol geometry.Tolerance,
+) {
+	moved := transform.Apply(s, anchor)
+	switch d := transform.DescribeWithin(s, tol).(type) {
+	case transform.LineReflectionParams:
+		p.add(path{
+			points: []geometry.Point{anchor, moved},
+			color:  colorMotion,
+			width:  1,
+			dashed: true,
+		})
+		p.add(line{l: d.Line, color: colorMirror, width: 2})
+	case transform.TranslationParams:
+		p.add(path{
+			points: []geometry.Point{anchor, moved},
+			color:  colorMotion,
+			width:  2,
+			arrow:  true,
+		})
+		p.add(label{
+			at:    midpoint(anchor, moved),
+			text:  d.Vector.String(),
+			color: colorMotion,
+		})
+	case transform.RotationParams:
+		c, rads := d.Center, d.Angle
+		ps := arc(c, anchor, rads)
+		p.include(c)
+		p.add(path{points: ps, color: colorMotion, width: 2, arrow: true})
+		p.add(dot{center: c, color: colorMotion, radius: 4})
+		p.add(label{at: c, text: c.String(), color: colorMotion})
+		p.add(label{
+			at:    ps[len(ps)/2],
+			text:  rads.String(),
+			color: colorMotion,
+		})
+	case transform.GlideParams:
+		axis, v := d.Axis, d.Vector
+		start := geometry.Project(axis, anchor)
+		end := geometry.Point{X: start.X + v.I, Y: start.Y + v.J}
+		p.include(end)
+		p.add(line{l: axis, color: colorMirror, width: 2})
+		p.add(path{
+			points: []geometry.Point{start, end},
+			color:  colorMotion,
+			width:  2,
+			arrow:  true,
+		})
+		p.add(label{
+			at:    midpoint(start, end),
+			text:  v.String(),
+			color: colorMotion,
+		})
+	}
+}
+
+// addSample adds geometry.Point a on the figure and where it's moved to, b, to
+// the panel as labelled dots.
+func (p *panel) addSample(a, b geometry.Point) {
+	p.add(dot{center: a, color: colorFigure, radius: 3})
+	p.add(label{at: a, text: "P " + a.String(), color: colorFigure})
+	p.add(dot{center: b, color: colorImage, radius: 3})
+	p.add(label{at: b, text: "P' " + b.String(), color: colorImage})
+}
+
+// include grows the panel's region so that all geometry.Points xs fit in it.
+func (p *panel) include(xs ...geometry.Point) {
+	for _, x := range xs {
+		if !p.sized {
+			p.min, p.max, p.sized = x, x, true
+		}
+		p.min.X = geometry.Number(math.Min(float64(p.min.X), float64(x.X)))
+		p.min.Y = geometry.Number(math.Min(float64(p.min.Y), float64(x.Y)))
+		p.max.X = geometry.Number(math.Max(float64(p.max.X), float64(x.X)))
+		p.max.Y = geometry.Number(math.Max(float64(p.max.Y), float64(x.Y)))
+	}
+}
+
+// add shape s to the panel.
+func (p *panel) add(s shape) {
+	p.shapes = append(p.shapes, s)
+}
+
+// frame squares the panel's region around its center, adds a margin, and
+// clips every line to the region.
+//
+// Must be called after all geometry.Points are included and all shapes are
+// added.
+func (p *panel) frame() {
+	c := p.center()
+	half := math.Max(
+		float64(p.max.X-p.min.X),
+		float64(p.max.Y-p.min.Y),
+	) * 0.6
+	h := geometry.Number(math.Max(half, 1))
+	p.min = geometry.Point{X: c.X - h, Y: c.Y - h}
+	p.max = geometry.Point{X: c.X + h, Y: c.Y + h}
+	var shapes []shape
+	for _, s := range p.shapes {
+		l, ok := s.(line)
+		if !ok {
+			shapes = append(shapes, s)
+			continue
+		}
+		c := p.clip(l)
+		if c == nil {
+			continue
+		}
+		shapes = append(shapes, *c)
+		if l.name != "" {
+			shapes = append(shapes, label{
+				at:    midpoint(c.points[0], c.points[1]),
+				text:  l.name,
+				color: l.color,
+			})
+		}
+	}
+	p.shapes = shapes
+}
+
+// pixel returns where geometry.Point x is in the panel with the top-left
+// corner of the panel at the origin and y increasing downwards.
+func (p panel) pixel(x geometry.Point) pixel {
+	span := float64(p.span())
+	return pixel{
+		x: float64(x.X-p.min.X) / span * panelSize,
+		y: panelSize - float64(x.Y-p.min.Y)/span*panelSize,
+	}
+}
+
+// place returns where the bottom-left corner of a label width pixels wide and
+// height pixels tall labelling geometry.Point x goes in the panel as described
+// by pixel.
+//
+// The label is moved right and up from x by labelOffset. It's flipped to the
+// other side of x if it would stick out of the panel there and is moved inside
+// the panel if it would still stick out.
+func (p panel) place(x geometry.Point, width, height float64) pixel {
+	at := p.pixel(x)
+	return pixel{
+		x: fit(at.x+labelOffset, at.x-labelOffset-width, width),
+		y: fit(at.y-labelOffset, at.y+labelOffset+height, -height),
+	}
+}
+
+// fit returns the first of the starts, a and b, of a span of size pixels that
+// lies inside the panel or the closest start to a that does if neither does.
+//
+// The span goes right or down from its start if size is positive and left or
+// up from it otherwise.
+func fit(a, b, size float64) float64 {
+	low, high := 0.0, panelSize-size
+	if size < 0 {
+		low, high = -size, panelSize
+	}
+	for _, start := range []float64{a, b} {
+		if start >= low && start <= high {
+			return start
+		}
+	}
+	return math.Max(low, math.Min(a, high))
+}
+
+// pixels returns where all geometry.Points xs are in the panel as described
+// by pixel.
+func (p panel) pixels(xs []geometry.Point) []pixel {
+	out := make([]pixel, len(xs))
+	for i, x := range xs {
+		out[i] = p.pixel(x)
+	}
+	return out
+}
+
+// center of the panel's region.
+func (p panel) center() geometry.Point {
+	return geometry.Point{
+		X: (p.min.X + p.max.X) / 2,
+		Y: (p.min.Y + p.max.Y) / 2,
+	}
+}
+
+// span is the width and height of the panel's region.
+func (p panel) span() geometry.Number {
+	return p.max.X - p.min.X
+}
+
+// clip line l to the panel's region and return it as a path centered on the
+// geometry.Point on l closest to the region's center.
+//
+// Returns nil if l doesn't pass through the region.
+func (p panel) clip(l line) *path {
+	m := geometry.Project(l.l, p.center())
+	if geometry.Distance(m, p.center()) > p.span()*math.Sqrt2/2 {
+		return nil
+	}
+	d := geometry.Direction(l.l)
+	reach := p.span()
+	return &path{
+		points: []geometry.Point{
+			{X: m.X - reach*d.I, Y: m.Y - reach*d.J},
+			{X: m.X + reach*d.I, Y: m.Y + reach*d.J},
+		},
+		color:  l.color,
+		width:  l.width,
+		dashed: l.dashed,
+	}
+}
+
+// placedFigure returns the figure scaled to size and centered at anchor.
+func placedFigure(anchor geometry.Point, size geometry.Number) []geometry.Point {
+	ps := make([]geometry.Point, len(figure))
+	for i, p := range figure {
+		ps[i] = geometry.Point{
+			X: anchor.X + size*p.X,
+			Y: anchor.Y + size*p.Y,
+		}
+	}
+	return ps
+}
+
+// arc returns geometry.Points along the arc from geometry.Point from rotated
+// counter-clockwise by geometry.Angle rads around geometry.Point c.
+//
+// rads is first turned into the equivalent geometry.Angle closest to 0.
+func arc(c, from geometry.Point, rads geometry.Angle) []geometry.Point {
+	sweep := math.Remainder(float64(rads), 2*math.Pi)
+	r := float64(geometry.Distance(c, from))
+	start := math.Atan2(float64(from.Y-c.Y), float64(from.X-c.X))
+	n := int(math.Ceil(math.Abs(sweep)/(math.Pi/64))) + 1
+	ps := make([]geometry.Point, n+1)
+	for i := 0; i <= n; i++ {
+		a := start + sweep*float64(i)/float64(n)
+		ps[i] = geometry.Point{
+			X: c.X + geometry.Number(r*math.Cos(a)),
+			Y: c.Y + geometry.Number(r*math.Sin(a)),
+		}
+	}
+	return ps
+}
+
+// midpoint returns the geometry.Point halfway between geometry.Points a and b.
+func midpoint(a, b geometry.Point) geometry.Point {
+	return geometry.Point{X: (a.X + b.X) / 2, Y: (a.Y + b.Y) / 2}
+}
+
+// offset returns the geometry.Point distance d away from geometry.Point p on
+// geometry.Line l along l's perpendicular.
+func offset(l geometry.Line, p geometry.Point, d geometry.Number) geometry.Point {
+	n := geometry.Direction(geometry.Perpendicular(l))
+	return geometry.Point{X: p.X + d*n.I, Y: p.Y + d*n.J}
+}
+
+// pixel is a location in a panel in pixels.
+type pixel struct{ x, y float64 }
+
+// head returns the triangle of an arrow head pointing from pixel a to pixel b
+// with its tip at b.
+//
+// Returns nil if a and b are the same.
+func head(a, b pixel) []pixel {
+	dx, dy := b.x-a.x, b.y-a.y
+	l := math.Hypot(dx, dy)
+	if l == 0 {
+		return nil
+	}
+	dx, dy = dx/l, dy/l
+	bx, by := b.x-headLength*dx, b.y-headLength*dy
+	return []pixel{
+		b,
+		{x: bx - headWidth/2*dy, y: by + headWidth/2*dx},
+		{x: bx + headWidth/2*dy, y: by - headWidth/2*dx},
+	}
+}
@@ -0,0 +1,159 @@
+package viz
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+)
+
+const (
+	// fontSize is the pixel height of text in SVGs.
+	fontSize = 14
+	// glyphAspect is the width of monospace glyphs divided by their height.
+	glyphAspect = 0.6
+)
+
+// svgWriter writes a scene as an SVG.
+//
+// The first error encountered while writing is kept and every later write is
+// skipped.
+type svgWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+// writeSVG writes scene s to io.Writer w as an SVG with each panel drawn side
+// by side on a white background.
+func writeSVG(w io.Writer, s scene) error {
+	sw := &svgWriter{w: bufio.NewWriter(w)}
+	width := panelSize * len(s)
+	sw.printf(
+		`<svg xmlns="http://www.w3.org/2000/svg" `+
+			`width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		width, panelSize, width, panelSize,
+	)
+	sw.printf(`<rect width="%d" height="%d" fill="white"/>`+"\n", width, panelSize)
+	for i, p := range s {
+		sw.panel(i, p)
+	}
+	sw.printf("</svg>\n")
+	if sw.err != nil {
+		return sw.err
+	}
+	return sw.w.Flush()
+}
+
+// panel writes panel p as the i-th panel from the left.
+func (sw *svgWriter) panel(i int, p panel) {
+	left := i * panelSize
+	sw.printf(`<clipPath id="panel%d">`, i)
+	sw.printf(`<rect width="%d" height="%d"/>`, panelSize, panelSize)
+	sw.printf("</clipPath>\n")
+	sw.printf(
+		`<g transform="translate(%d 0)" clip-path="url(#panel%d)">`+"\n",
+		left, i,
+	)
+	for _, s := range p.shapes {
+		sw.shape(p, s)
+	}
+	sw.text(
+		pixel{x: titleMargin, y: titleMargin + fontSize},
+		p.title, colorText,
+	)
+	sw.printf("</g>\n")
+	if i > 0 {
+		sw.printf(
+			`<line x1="%d" y1="0" x2="%d" y2="%d" stroke="%s"/>`+"\n",
+			left, left, panelSize, hex(colorSeparator),
+		)
+	}
+}
+
+// shape writes shape s drawn in panel p.
+func (sw *svgWriter) shape(p panel, s shape) {
+	switch s := s.(type) {
+	case path:
+		ps := p.pixels(s.points)
+		dash := ""
+		if s.dashed {
+			dash = fmt.Sprintf(` stroke-dasharray="%d %d"`, dashLength, gapLength)
+		}
+		sw.printf(
+			`<polyline points="%s" fill="none" stroke="%s" `+
+				`stroke-width="%g"%s/>`+"\n",
+			points(ps), hex(s.color), s.width, dash,
+		)
+		if s.arrow && len(ps) > 1 {
+			sw.printf(
+				`<polygon points="%s" fill="%s"/>`+"\n",
+				points(head(ps[len(ps)-2], ps[len(ps)-1])),
+				hex(s.color),
+			)
+		}
+	case polygon:
+		sw.printf(
+			`<polygon points="%s" fill="%s" fill-opacity="%.3f" `+
+				`stroke="%s" stroke-width="2"/>`+"\n",
+			points(p.pixels(s.points)),
+			hex(s.color),
+			float64(fillAlpha)/0xFF,
+			hex(s.color),
+		)
+	case dot:
+		c := p.pixel(s.center)
+		sw.printf(
+			`<circle cx="%.2f" cy="%.2f" r="%g" fill="%s"/>`+"\n",
+			c.x, c.y, s.radius, hex(s.color),
+		)
+	case label:
+		x := p.place(s.at, svgTextWidth(s.text), fontSize)
+		sw.text(x, s.text, s.color)
+	}
+}
+
+// text writes string t in the given color with its bottom-left corner at
+// pixel x.
+func (sw *svgWriter) text(x pixel, t string, c color.NRGBA) {
+	var b strings.Builder
+	if err := xml.EscapeText(&b, []byte(t)); err != nil && sw.err == nil {
+		sw.err = err
+	}
+	sw.printf(
+		`<text x="%.2f" y="%.2f" fill="%s" font-family="monospace" `+
+			`font-size="%d">%s</text>`+"\n",
+		x.x, x.y, hex(c), fontSize, b.String(),
+	)
+}
+
+// svgTextWidth returns about how many pixels wide string t is when written by
+// svgWriter.text.
+//
+// Monospace glyphs are about glyphAspect times as wide as they are tall.
+func svgTextWidth(t string) float64 {
+	return float64(len([]rune(t))) * fontSize * glyphAspect
+}
+
+// printf writes the formatted string if no error has been encountered yet.
+func (sw *svgWriter) printf(format string, args ...interface{}) {
+	if sw.err != nil {
+		return
+	}
+	_, sw.err = fmt.Fprintf(sw.w, format, args...)
+}
+
+// points returns pixels ps formatted as an SVG points-attribute.
+func points(ps []pixel) string {
+	xs := make([]string, len(ps))
+	for i, p := range ps {
+		xs[i] = fmt.Sprintf("%.2f,%.2f", p.x, p.y)
+	}
+	return strings.Join(xs, " ")
+}
+
+// hex returns the hex-code for color c ignoring opacity.
+func hex(c color.NRGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -1,136 +1,132 @@
+// Package viz vizualizes transform.Transformations by drawing a sample figure
+// before and after the transform.Transformation along with the parts that
+// define it like geometry.Lines reflected across, geometry.Vectors translated
+// by, and geometry.Points rotated around.
 package viz
 
 import (
 	"image"
-	"image/color"
+	"image/gif"
+	"io"
 
 	"github.com/jwowillo/viztransform/geometry"
 	"github.com/jwowillo/viztransform/transform"
 )
 
-const (
-	width  = 500
-	height = 500
-)
-
-func newSetImage() setImage {
-	// TODO: Fill the canvas with white.
-	return image.NewRGBA(image.Rect(0, 0, width, height))
-}
-
-// Transformation ...
+// Transformation returns an image.Image vizualizing transform.Transformation t.
+//
+// The image.Image has a single panel demonstrating t if t is already
+// simplified. Otherwise, it has a panel demonstrating each line-reflection
+// making up t followed by a panel demonstrating the simplified form of t. Each
+// panel is titled and the parts of t are labelled.
+//
+// The figure is blue before being transformed and red after. Lines being
+// reflected across are black, individual line-reflections of a
+// transform.Transformation that isn't simplified are dashed and gray, and
+// geometry.Vectors, rotation-arcs, and rotation-centers are green.
+//
+// Panics like transform.Describe if t can't be simplified and described.
 func Transformation(t transform.Transformation) image.Image {
-	img := newSetImage()
-	if transform.IsSimplified(t) {
-		switch transform.TypeOf(t) {
-		case transform.TypeNoTransformation:
-			// Something.
-		case transform.TypeLineReflection:
-			img = lineReflection(img, t[0])
-		case transform.TypeTranslation:
-			// return translation(t)
-		case transform.TypeRotation:
-			// return rotation(t)
-		case transform.TypeGlideReflection:
-			// return glideReflection(t)
-		}
-	} else {
+	img, err := TransformationWithin(t, geometry.DefaultTolerance())
+	if err != nil {
+		panic(err)
 	}
 	return img
 }
 
-type setImage interface {
-	image.Image
-	Set(int, int, color.Color)
-}
-
-func lineReflection(img setImage, l geometry.Line) setImage {
-	return nil
-}
-
-/*
-// LineReflection ...
-func LineReflection(w io.Writer, l transform.LineReflection) error {
-	// Make sure scaling works so I don't have to think super hard about
-	// where things go. Set canvas size in var somewhere.
-
-	// Pick 4 points located conviently around the LineReflection's Line.
-	// Plot all of them.
-	// Plot the Line
-	// Plot all their perpendiculars to the Line with a right angle sign on
-	// the line.
-	// Plot other Reflected Points, also with normal line.
-
-	// Abstract a function that given a Line and a Point, draws the Point
-	// and the normal to the Line.
-	// If the distance from the Line is 0, skip.
-
-	// Label the distances.
-
-	canvas := image.NewRGBA(image.Rect(0, 0, 500, 500))
-	fillWhite(canvas)
-	drawLine(canvas, -200, -53, 100, 50)
-	return png.Encode(w, canvas)
-}
-
-// Translation ...
-func Translation(w io.Writer, t transform.Translation) error {
-	return nil
-}
-
-// Rotation ...
-func Rotation(w io.Writer, r transform.Rotation) error {
-	return nil
-}
-
-// GlideReflection ...
-func GlideReflection(w io.Writer, g transform.GlideReflection) error {
-	return nil
+// TransformationWithin is Transformation where geometry.Lines are compared
+// within geometry.Tolerance tol when simplifying and describing t.
+//
+// Returns the transform.InstabilityError transform.DescribeWithinE does
+// instead of panicking.
+func TransformationWithin(
+	t transform.Transformation,
+	tol geometry.Tolerance,
+) (image.Image, error) {
+	s, err := simplify(t, tol)
+	if err != nil {
+		return nil, err
+	}
+	return rasterize(newScene(t, s, tol)), nil
 }
 
-// Composition ...
-func Composition(w io.Writer, c transform.Composition) error {
-	return nil
+// SVG writes an SVG vizualizing transform.Transformation t to io.Writer w.
+//
+// The SVG shows the same thing as the image.Image returned by Transformation.
+//
+// Returns an error if writing to w fails and the transform.InstabilityError
+// transform.DescribeE does if t can't be simplified and described.
+func SVG(w io.Writer, t transform.Transformation) error {
+	return SVGWithin(w, t, geometry.DefaultTolerance())
 }
 
-func fillWhite(canvas *image.RGBA) {
-	for i := 0; i <= 500; i++ {
-		for j := 0; j <= 500; j++ {
-			canvas.Set(
-				i, j,
-				color.RGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 0xFF},
-			)
-		}
+// SVGWithin is SVG where geometry.Lines are compared within
+// geometry.Tolerance tol when simplifying and describing t.
+func SVGWithin(
+	w io.Writer,
+	t transform.Transformation,
+	tol geometry.Tolerance,
+) error {
+	s, err := simplify(t, tol)
+	if err != nil {
+		return err
 	}
+	return writeSVG(w, newScene(t, s, tol))
 }
 
-func drawLine(canvas *image.RGBA, x1, y1, x2, y2 float64) {
-	y1, y2 = -1*y1, -1*y2
-	x, y := x1, y1
-	m := (y2 - y1) / (x2 - x1)
-	e := 0.001
-	for math.Abs(x-x2) > e && math.Abs(y-y2) > e {
-		drawPoint(canvas, x, y)
-		x++
-		y += m
+// Animation returns an animated GIF vizualizing transform.Transformation t step
+// by step.
+//
+// The figure is folded across each line-reflection making up t in order with
+// the active line-reflection drawn in black. The figure is then moved from
+// where it started by the simplified form of t and the animation finishes on a
+// frame showing the simplified form like Transformation does.
+//
+// Panics like transform.Describe if t can't be simplified and described.
+func Animation(t transform.Transformation) *gif.GIF {
+	g, err := AnimationWithin(t, geometry.DefaultTolerance())
+	if err != nil {
+		panic(err)
 	}
+	return g
 }
 
-func round(x float64) int {
-	return int(math.Floor(x + .5))
+// AnimationWithin is Animation where geometry.Lines are compared within
+// geometry.Tolerance tol when simplifying and describing t.
+//
+// Returns the transform.InstabilityError transform.DescribeWithinE does
+// instead of panicking.
+func AnimationWithin(
+	t transform.Transformation,
+	tol geometry.Tolerance,
+) (*gif.GIF, error) {
+	s, err := simplify(t, tol)
+	if err != nil {
+		return nil, err
+	}
+	g := &gif.GIF{}
+	for _, f := range newAnimation(t, s, tol) {
+		g.Image = append(g.Image, paletted(rasterize(scene{f.p})))
+		g.Delay = append(g.Delay, f.delay)
+	}
+	return g, nil
 }
 
-func drawPoint(canvas *image.RGBA, x, y float64) {
-	const n = 2
-	ix, iy := round(x)+250, round(y)+250
-	for i := ix - n; i <= ix+n; i++ {
-		for j := iy - n; j <= iy+n; j++ {
-			di, dj := i-ix, j-iy
-			if di*di+dj*dj > n*n {
-				continue
-			}
-			canvas.Set(i, j, color.RGBA{R: 0xFF, A: 0xFF})
-		}
+// simplify Transformation t within geometry.Tolerance tol and check the
+// simplified form can be described so drawing it can't panic.
+//
+// Returns the transform.InstabilityError transform.DescribeWithinE does if
+// either fails.
+func simplify(
+	t transform.Transformation,
+	tol geometry.Tolerance,
+) (transform.Transformation, error) {
+	s, err := transform.SimplifyWithinE(t, tol)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := transform.DescribeWithinE(s, tol); err != nil {
+		return nil, err
 	}
+	return s, nil
 }
-*/
@@ -0,0 +1,216 @@
+package viz
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/gif"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/jwowillo/viztransform/geometry"
+	"github.com/jwowillo/viztransform/transform"
+)
+
+// testLine returns the geometry.Line through (ax ay) and (bx by).
+func testLine(ax, ay, bx, by geometry.Number) geometry.Line {
+	return geometry.MustLine(geometry.NewLineFromPoints(
+		geometry.Point{X: ax, Y: ay},
+		geometry.Point{X: bx, Y: by},
+	))
+}
+
+// testTransformations are vizualized to check every kind of
+// transform.Transformation, simplified or not, can be drawn.
+var testTransformations = []struct {
+	name string
+	t    transform.Transformation
+	// panels is how many panels the vizualization should have.
+	panels int
+}{
+	{"no transformation", transform.NoTransformation(), 1},
+	{
+		"line reflection",
+		transform.LineReflection(testLine(0, 0, 1, 1)),
+		1,
+	},
+	{
+		"translation",
+		transform.Translation(geometry.Vector{I: 3, J: 0}),
+		1,
+	},
+	{
+		"rotation",
+		transform.Rotation(geometry.Point{X: 1, Y: 2}, math.Pi/2),
+		1,
+	},
+	{
+		"glide reflection",
+		transform.GlideReflection(
+			testLine(0, 0, 1, 0),
+			geometry.Vector{I: 2, J: 0},
+		),
+		1,
+	},
+	{
+		"translation then translation",
+		transform.Compose(
+			transform.Translation(geometry.Vector{I: 3, J: 0}),
+			transform.Translation(geometry.Vector{I: 0, J: 2}),
+		),
+		2,
+	},
+}
+
+// TestTransformation checks every testTransformation is drawn as a PNG that
+// decodes and has the right number of panels.
+func TestTransformation(t *testing.T) {
+	for _, c := range testTransformations {
+		img, err := TransformationWithin(c.t, geometry.DefaultTolerance())
+		if err != nil {
+			t.Errorf("%s: TransformationWithin gives %v", c.name, err)
+			continue
+		}
+		var b bytes.Buffer
+		if err := png.Encode(&b, img); err != nil {
+			t.Errorf("%s: png.Encode gives %v", c.name, err)
+			continue
+		}
+		got, err := png.Decode(&b)
+		if err != nil {
+			t.Errorf("%s: png.Decode gives %v", c.name, err)
+			continue
+		}
+		want := c.panels * panelSize
+		if w, h := got.Bounds().Dx(), got.Bounds().Dy(); w != want ||
+			h != panelSize {
+			t.Errorf("%s: PNG is %dx%d but should be %dx%d",
+				c.name, w, h, want, panelSize)
+		}
+	}
+}
+
+// TestSVG checks every testTransformation is written as an SVG that decodes
+// as XML and has the right size and number of panels.
+func TestSVG(t *testing.T) {
+	for _, c := range testTransformations {
+		var b bytes.Buffer
+		if err := SVGWithin(&b, c.t, geometry.DefaultTolerance()); err != nil {
+			t.Errorf("%s: SVGWithin gives %v", c.name, err)
+			continue
+		}
+		var root *xml.StartElement
+		panels := 0
+		d := xml.NewDecoder(&b)
+		for {
+			tok, err := d.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Errorf("%s: SVG doesn't decode: %v", c.name, err)
+				break
+			}
+			e, ok := tok.(xml.StartElement)
+			if !ok {
+				continue
+			}
+			if root == nil {
+				root = &e
+			}
+			if e.Name.Local == "g" {
+				panels++
+			}
+		}
+		if root == nil || root.Name.Local != "svg" {
+			t.Errorf("%s: SVG doesn't have an svg root", c.name)
+			continue
+		}
+		want := strconv.Itoa(c.panels * panelSize)
+		if got := attr(*root, "width"); got != want {
+			t.Errorf("%s: SVG is %s wide but should be %s wide",
+				c.name, got, want)
+		}
+		if panels != c.panels {
+			t.Errorf("%s: SVG has %d panels but should have %d",
+				c.name, panels, c.panels)
+		}
+	}
+}
+
+// attr returns the value of the attribute of xml.StartElement e with the given
+// name or the empty string if there isn't one.
+func attr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// TestAnimation checks every testTransformation is animated as a GIF that
+// decodes and has a delay for every frame.
+func TestAnimation(t *testing.T) {
+	for _, c := range testTransformations {
+		g, err := AnimationWithin(c.t, geometry.DefaultTolerance())
+		if err != nil {
+			t.Errorf("%s: AnimationWithin gives %v", c.name, err)
+			continue
+		}
+		var b bytes.Buffer
+		if err := gif.EncodeAll(&b, g); err != nil {
+			t.Errorf("%s: gif.EncodeAll gives %v", c.name, err)
+			continue
+		}
+		got, err := gif.DecodeAll(&b)
+		if err != nil {
+			t.Errorf("%s: gif.DecodeAll gives %v", c.name, err)
+			continue
+		}
+		if len(got.Image) == 0 || len(got.Image) != len(got.Delay) {
+			t.Errorf("%s: GIF has %d frames and %d delays",
+				c.name, len(got.Image), len(got.Delay))
+		}
+	}
+}
+
+// TestPlace checks labels are flipped or moved to stay inside their panel.
+func TestPlace(t *testing.T) {
+	p := panel{max: geometry.Point{X: panelSize, Y: panelSize}}
+	const width, height = 100, 20
+	cases := []struct {
+		at   geometry.Point
+		want pixel
+	}{
+		{
+			geometry.Point{X: 250, Y: 250},
+			pixel{x: 250 + labelOffset, y: 250 - labelOffset},
+		},
+		{
+			geometry.Point{X: 450, Y: 250},
+			pixel{x: 450 - labelOffset - width, y: 250 - labelOffset},
+		},
+		{
+			geometry.Point{X: 250, Y: 490},
+			pixel{x: 250 + labelOffset, y: 10 + labelOffset + height},
+		},
+		{
+			geometry.Point{X: 0, Y: 0},
+			pixel{x: labelOffset, y: panelSize - labelOffset},
+		},
+	}
+	for _, c := range cases {
+		if got := p.place(c.at, width, height); got != c.want {
+			t.Errorf("place(%v) is %v but should be %v", c.at, got, c.want)
+		}
+	}
+	wide := float64(panelSize + 100)
+	got := p.place(geometry.Point{X: 250, Y: 250}, wide, height)
+	if got.x != 0 {
+		t.Errorf("label %v pixels wide starts at %v but should start at 0",
+			wide, got.x)
+	}
+}
@@ -1,5 +1,5 @@
 // Package viztransform is the parent package of all viztransform packages.
 //
-// These include geometry, transform, and parse along with all commands defined
-// in cmd.
+// These include geometry, transform, exact, parse, and viz along with all
+// commands defined in cmd.
 package viztransform